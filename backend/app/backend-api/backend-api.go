@@ -1,12 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"test-task3/libs/1_domain_methods/handlers/admin"
 	"test-task3/libs/1_domain_methods/handlers/auth"
+	"test-task3/libs/1_domain_methods/handlers/oauth"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/mfa"
+	"test-task3/libs/1_domain_methods/passwords"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/3_infrastructure/auditor"
 	"test-task3/libs/3_infrastructure/db_manager"
+	"test-task3/libs/3_infrastructure/mailer"
+	"test-task3/libs/3_infrastructure/rate_limiter"
+	"test-task3/libs/3_infrastructure/token_service"
 	"test-task3/libs/4_common/env_vars"
 	"test-task3/libs/4_common/smart_context"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	chi_middleware "github.com/go-chi/chi/v5/middleware"
@@ -23,26 +39,107 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Error connecting to database: %v", err)
 	}
+
+	if err := helpers.ConfigureTrustedProxies(strings.Split(os.Getenv("TRUSTED_PROXIES"), ",")); err != nil {
+		logger.Fatalf("Error parsing TRUSTED_PROXIES: %v", err)
+	}
+
+	ipBindingPolicy, err := helpers.ParseIPBindingPolicy(os.Getenv("JWT_IP_BINDING"))
+	if err != nil {
+		logger.Fatalf("Error parsing JWT_IP_BINDING: %v", err)
+	}
+	helpers.ConfigureIPBinding(ipBindingPolicy)
+
+	configured, err := mfa.ConfigureEncryptionKey(os.Getenv("MFA_ENCRYPTION_KEY"), dbm.GetJwtSecret())
+	if err != nil {
+		logger.Fatalf("Error parsing MFA_ENCRYPTION_KEY: %v", err)
+	}
+	if !configured {
+		logger.Warn("MFA_ENCRYPTION_KEY is not set, TOTP secrets are encrypted with a key derived from JWT_SECRET")
+	}
+
+	if err := passwords.Configure(passwords.LoadConfig(logger)); err != nil {
+		logger.Fatalf("Error configuring password hashing: %v", err)
+	}
+	sessions.Configure(sessions.LoadConfig(logger))
+
+	sessionLimits, err := sessions.LoadLimitConfig(logger)
+	if err != nil {
+		logger.Fatalf("Error parsing SESSION_LIMIT_POLICY: %v", err)
+	}
+	sessions.ConfigureLimits(sessionLimits)
+
 	logger = logger.WithDbManager(dbm)
 	logger = logger.WithDB(dbm.GetGORM())
 
+	tokenConfig := token_service.LoadConfig(logger)
+	revocations := token_service.NewRevocationStore(logger, dbm.GetGORM(), tokenConfig.RevocationCacheTTL, tokenConfig.MaxAccessTTL+tokenConfig.Leeway)
+	idTokenKey, err := token_service.LoadIDTokenKey(logger)
+	if err != nil {
+		logger.Fatalf("Error loading OIDC_SIGNING_KEY_FILE: %v", err)
+	}
+	logger = logger.WithTokenService(token_service.NewJwtTokenService(dbm.GetJwtSecret(), tokenConfig, revocations).WithIDTokenKey(idTokenKey))
+
+	rateLimiter, err := rate_limiter.NewRateLimiter(logger)
+	if err != nil {
+		logger.Fatalf("Error creating rate limiter: %v", err)
+	}
+	logger = logger.WithRateLimiter(rateLimiter)
+
+	mail, err := mailer.NewMailer(logger)
+	if err != nil {
+		logger.Fatalf("Error creating mailer: %v", err)
+	}
+	logger = logger.WithMailer(mail)
+
+	logger = logger.WithAuditor(auditor.NewPostgresAuditor(logger, dbm.GetGORM()))
+
+	// фоновые задачи останавливаются вместе с сервером
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	auditor.StartRetention(logger.WithContext(ctx), time.Duration(env_vars.GetEnvAsInt(logger, "AUDIT_RETENTION_DAYS", 90))*24*time.Hour)
+
+	janitorConfig := sessions.LoadJanitorConfig(logger)
+	if err := janitorConfig.Validate(); err != nil {
+		logger.Fatalf("Error configuring refresh token janitor: %v", err)
+	}
+	sessions.StartJanitor(logger.WithContext(ctx), janitorConfig)
+
 	r := chi.NewRouter()
 
+	r.Use(chi_middleware.RequestID)
 	r.Use(chi_middleware.Logger)
 	r.Use(chi_middleware.Recoverer)
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "HEAD"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With", "X-Request-Id", "X-Session-Id", "Apikey", "X-Api-Key"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Requested-With", "X-Request-Id", "X-Session-Id", "Apikey", "X-Api-Key", "X-Device-Name"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
 	auth.AuthRoutes(r, logger)
+	oauth.OAuthRoutes(r, logger)
+	admin.AdminRoutes(r, logger)
+
+	server := &http.Server{Addr: ":4000", Handler: r}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		logger.Info("Shutting down server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("server shutdown error: %v", err)
+		}
+	}()
 
 	logger.Info("Server listening on port 4000")
-	err = http.ListenAndServe(":4000", r)
-	logger.Fatal(err)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		logger.Fatal(err)
+	}
+	<-stopped
 }
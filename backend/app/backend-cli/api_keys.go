@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"test-task3/libs/1_domain_methods/api_keys"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+func apiKeysCommand(sctx smart_context.ISmartContext, subcommand string, args []string) error {
+	flags := flag.NewFlagSet("api-keys "+subcommand, flag.ContinueOnError)
+
+	switch subcommand {
+	case "list":
+		userId := flags.String("user-id", "", "owner id")
+		email := flags.String("email", "", "owner email, instead of -user-id")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		ownerId, err := resolveUserID(sctx, *userId, *email)
+		if err != nil {
+			return err
+		}
+
+		keys, err := api_keys.List(sctx, ownerId)
+		if err != nil {
+			return err
+		}
+		return printJSON(keys)
+
+	case "create":
+		userId := flags.String("user-id", "", "owner id")
+		email := flags.String("email", "", "owner email, instead of -user-id")
+		name := flags.String("name", "", "key name")
+		scopes := flags.String("scopes", "", "comma-separated scopes, all owner permissions if empty")
+		expiresDays := flags.Int("expires-days", 0, "days until the key expires, 0 for no expiry")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		ownerId, err := resolveUserID(sctx, *userId, *email)
+		if err != nil {
+			return err
+		}
+
+		req := api_keys.NewKey{Name: *name, Scopes: splitList(*scopes)}
+		if *expiresDays > 0 {
+			expiresAt := time.Now().AddDate(0, 0, *expiresDays)
+			req.ExpiresAt = &expiresAt
+		}
+
+		key, err := api_keys.Create(sctx, ownerId, req)
+		if err != nil {
+			return err
+		}
+		return printJSON(key)
+
+	case "rotate":
+		id := flags.String("id", "", "key id")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		if *id == "" {
+			return errors.New("missing -id")
+		}
+
+		rotated, err := api_keys.Rotate(sctx, "", *id)
+		if err != nil {
+			return err
+		}
+		return printJSON(rotated)
+
+	case "revoke":
+		id := flags.String("id", "", "key id")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *id == "" {
+			return errors.New("missing -id")
+		}
+
+		if err := api_keys.Revoke(sctx, "", *id); err != nil {
+			return err
+		}
+		fmt.Printf("api key %s revoked\n", *id)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown api-keys subcommand %q", subcommand)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"test-task3/libs/3_infrastructure/db_manager"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+)
+
+const usage = `usage: backend-cli <command> <subcommand> [flags]
+
+commands:
+  clients list
+  clients create -name NAME [-id ID] [-redirect-uris URI,...] [-scopes SCOPE,...] [-ttl SECONDS] [-confidential]
+  clients rotate-secret -id ID [-overlap SECONDS]
+  clients delete -id ID
+  roles list
+  roles save -name NAME [-description TEXT] [-permissions PERMISSION,...] [-max-sessions N]
+  roles assign (-user-id ID | -email EMAIL) -role NAME
+  roles remove (-user-id ID | -email EMAIL) -role NAME
+  api-keys list (-user-id ID | -email EMAIL)
+  api-keys create (-user-id ID | -email EMAIL) [-name NAME] [-scopes SCOPE,...] [-expires-days DAYS]
+  api-keys rotate -id ID
+  api-keys revoke -id ID
+  sessions set-limit (-user-id ID | -email EMAIL) -max-sessions N
+  tokens cleanup [-retention SECONDS] [-batch-size N] [-mode delete|archive]
+`
+
+func main() {
+	env_vars.LoadEnvVars() // load env vars from .env file if ENV_PATH is specified
+
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	logger := smart_context.NewSmartContext()
+
+	dbm, err := db_manager.NewDbManager(logger)
+	if err != nil {
+		logger.Fatalf("Error connecting to database: %v", err)
+	}
+	logger = logger.WithDbManager(dbm)
+	logger = logger.WithDB(dbm.GetGORM())
+
+	command, subcommand, args := os.Args[1], os.Args[2], os.Args[3:]
+
+	switch command {
+	case "clients":
+		err = clientsCommand(logger, subcommand, args)
+	case "roles":
+		err = rolesCommand(logger, subcommand, args)
+	case "api-keys":
+		err = apiKeysCommand(logger, subcommand, args)
+	case "sessions":
+		err = sessionsCommand(logger, subcommand, args)
+	case "tokens":
+		err = tokensCommand(logger, subcommand, args)
+	default:
+		err = fmt.Errorf("unknown command %q", command)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n\n%s", err, usage)
+		os.Exit(1)
+	}
+}
+
+func printJSON(data interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
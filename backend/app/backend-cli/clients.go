@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"test-task3/libs/1_domain_methods/handlers/oauth"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+func clientsCommand(sctx smart_context.ISmartContext, subcommand string, args []string) error {
+	flags := flag.NewFlagSet("clients "+subcommand, flag.ContinueOnError)
+
+	switch subcommand {
+	case "list":
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		clients, err := oauth.ListClients(sctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(clients)
+
+	case "create":
+		id := flags.String("id", "", "client id, generated if empty")
+		name := flags.String("name", "", "client name")
+		redirectURIs := flags.String("redirect-uris", "", "comma-separated redirect URIs")
+		scopes := flags.String("scopes", "", "comma-separated allowed scopes")
+		ttl := flags.Int("ttl", 0, "access token TTL in seconds, 0 for the default")
+		confidential := flags.Bool("confidential", false, "issue a client secret (required for client_credentials)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		client, err := oauth.CreateClient(sctx, oauth.NewClient{
+			ID:                    *id,
+			Name:                  *name,
+			RedirectURIs:          splitList(*redirectURIs),
+			Confidential:          *confidential,
+			AllowedScopes:         splitList(*scopes),
+			AccessTokenTTLSeconds: *ttl,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(client)
+
+	case "rotate-secret":
+		id := flags.String("id", "", "client id")
+		overlap := flags.Int("overlap", -1, "seconds the previous secret stays valid, OAUTH_CLIENT_SECRET_OVERLAP_SECONDS by default")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		window := oauth.LoadConfig(sctx).SecretOverlap
+		if *overlap >= 0 {
+			window = time.Duration(*overlap) * time.Second
+		}
+
+		client, err := oauth.RotateClientSecret(sctx, *id, window)
+		if err != nil {
+			return err
+		}
+		return printJSON(client)
+
+	case "delete":
+		id := flags.String("id", "", "client id")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *id == "" {
+			return errors.New("missing -id")
+		}
+
+		if err := oauth.DeleteClient(sctx, *id); err != nil {
+			return err
+		}
+		fmt.Printf("client %s deleted\n", *id)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown clients subcommand %q", subcommand)
+	}
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
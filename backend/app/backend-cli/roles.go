@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+)
+
+func rolesCommand(sctx smart_context.ISmartContext, subcommand string, args []string) error {
+	flags := flag.NewFlagSet("roles "+subcommand, flag.ContinueOnError)
+
+	switch subcommand {
+	case "list":
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		roles, err := access.ListRoles(sctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(roles)
+
+	case "save":
+		name := flags.String("name", "", "role name")
+		description := flags.String("description", "", "role description")
+		permissions := flags.String("permissions", "", "comma-separated permissions, replaces the current set")
+		maxSessions := flags.Int("max-sessions", 0, "active session limit for role members, 0 to inherit SESSION_LIMIT, negative for no limit")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		return access.SaveRole(sctx, access.RoleView{
+			Name:        *name,
+			Description: *description,
+			Permissions: splitList(*permissions),
+			MaxSessions: *maxSessions,
+		})
+
+	case "assign", "remove":
+		userId := flags.String("user-id", "", "user id")
+		email := flags.String("email", "", "user email, instead of -user-id")
+		role := flags.String("role", "", "role name")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *role == "" {
+			return errors.New("missing -role")
+		}
+
+		id, err := resolveUserID(sctx, *userId, *email)
+		if err != nil {
+			return err
+		}
+
+		if subcommand == "assign" {
+			if err := access.AssignRole(sctx, id, *role); err != nil {
+				return err
+			}
+			sctx.Warnf("Role %s assigned to user %s from CLI", *role, id)
+			fmt.Printf("role %s assigned to user %s\n", *role, id)
+			return nil
+		}
+
+		removed, err := access.RemoveRole(sctx, id, *role)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return fmt.Errorf("user %s has no role %s", id, *role)
+		}
+		sctx.Warnf("Role %s removed from user %s from CLI", *role, id)
+		fmt.Printf("role %s removed from user %s\n", *role, id)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown roles subcommand %q", subcommand)
+	}
+}
+
+func resolveUserID(sctx smart_context.ISmartContext, userId, email string) (string, error) {
+	if userId != "" {
+		return userId, nil
+	}
+	if email == "" {
+		return "", errors.New("missing -user-id or -email")
+	}
+
+	var user model.User
+	if err := sctx.GetDB().Where("lower(email) = ?", strings.ToLower(strings.TrimSpace(email))).First(&user).Error; err != nil {
+		return "", fmt.Errorf("user %s: %w", email, err)
+	}
+	return user.ID, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/4_common/smart_context"
+)
+
+func sessionsCommand(sctx smart_context.ISmartContext, subcommand string, args []string) error {
+	flags := flag.NewFlagSet("sessions "+subcommand, flag.ContinueOnError)
+
+	switch subcommand {
+	case "set-limit":
+		userId := flags.String("user-id", "", "user id")
+		email := flags.String("email", "", "user email, instead of -user-id")
+		maxSessions := flags.Int("max-sessions", 0, "active session limit, 0 to inherit from roles, negative for no limit")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		id, err := resolveUserID(sctx, *userId, *email)
+		if err != nil {
+			return err
+		}
+
+		if err := sessions.SetUserMaxSessions(sctx, id, *maxSessions); err != nil {
+			return err
+		}
+		sctx.Warnf("Session limit of user %s set to %d from CLI", id, *maxSessions)
+		fmt.Printf("session limit of user %s set to %d\n", id, *maxSessions)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown sessions subcommand %q", subcommand)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+func tokensCommand(sctx smart_context.ISmartContext, subcommand string, args []string) error {
+	flags := flag.NewFlagSet("tokens "+subcommand, flag.ContinueOnError)
+
+	switch subcommand {
+	case "cleanup":
+		cfg := sessions.LoadJanitorConfig(sctx)
+		retention := flags.Int("retention", int(cfg.Retention.Seconds()), "keep used, revoked and expired tokens for this many seconds")
+		batchSize := flags.Int("batch-size", cfg.BatchSize, "rows per batch")
+		mode := flags.String("mode", cfg.Mode, "delete or archive")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+
+		cfg.Retention = time.Duration(*retention) * time.Second
+		cfg.BatchSize = *batchSize
+		cfg.Mode = *mode
+
+		result, err := sessions.Cleanup(sctx, cfg)
+		if err != nil {
+			return err
+		}
+		return printJSON(result)
+
+	default:
+		return fmt.Errorf("unknown tokens subcommand %q", subcommand)
+	}
+}
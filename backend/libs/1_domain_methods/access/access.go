@@ -0,0 +1,198 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleAdmin — роль с доступом к /admin, создаётся миграцией
+const RoleAdmin = "admin"
+
+// ScopeRoles — scope, по которому OAuth2-клиент получает в токене роли пользователя
+const ScopeRoles = "roles"
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidName  = errors.New("invalid role or permission name")
+)
+
+// UserAccess — роли пользователя и permissions, которые они дают
+type UserAccess struct {
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleView struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	// MaxSessions — лимит активных сессий участников роли: 0 — не задан, отрицательный — без ограничения
+	MaxSessions int `json:"max_sessions"`
+}
+
+// LoadUserAccess читает роли пользователя и объединение их permissions
+func LoadUserAccess(sctx smart_context.ISmartContext, userId string) (*UserAccess, error) {
+	result := &UserAccess{Roles: []string{}, Permissions: []string{}}
+
+	err := sctx.GetDB().Model(&model.UserRole{}).
+		Where("user_id = ?", userId).
+		Order("role").
+		Pluck("role", &result.Roles).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Roles) == 0 {
+		return result, nil
+	}
+
+	err = sctx.GetDB().Model(&model.RolePermission{}).
+		Where("role IN ?", result.Roles).
+		Distinct("permission").
+		Order("permission").
+		Pluck("permission", &result.Permissions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// oidcScopes — стандартные scope OpenID Connect (OIDC Core, 5.4): они не являются permissions
+var oidcScopes = []string{"openid", "email", "profile"}
+
+// FilterScope оставляет из запрошенного клиентом scope только scope OpenID Connect, roles и permissions,
+// которые у пользователя есть. Остальное отбрасывается, в том числе permissions, ещё не заведённые
+// в таблице permissions: маршрут под RequireScope не должен открываться клиенту, который просто попросил scope
+func FilterScope(requested string, granted []string) string {
+	scopes := strings.Fields(requested)
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if slices.Contains(oidcScopes, scope) || scope == ScopeRoles || slices.Contains(granted, scope) {
+			result = append(result, scope)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+func ListRoles(sctx smart_context.ISmartContext) ([]RoleView, error) {
+	var roles []model.Role
+	if err := sctx.GetDB().Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	var links []model.RolePermission
+	if err := sctx.GetDB().Order("permission").Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	views := make([]RoleView, 0, len(roles))
+	for _, role := range roles {
+		view := RoleView{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: []string{},
+			MaxSessions: int(role.MaxSessions),
+		}
+		for _, link := range links {
+			if link.Role == role.Name {
+				view.Permissions = append(view.Permissions, link.Permission)
+			}
+		}
+		views = append(views, view)
+	}
+	return views, nil
+}
+
+// SaveRole создаёт роль или обновляет её описание и лимит сессий и заменяет набор permissions.
+// Недостающие permissions создаются
+func SaveRole(sctx smart_context.ISmartContext, role RoleView) error {
+	if !validName(role.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, role.Name)
+	}
+	for _, permission := range role.Permissions {
+		if !validName(permission) {
+			return fmt.Errorf("%w: %q", ErrInvalidName, permission)
+		}
+	}
+
+	return sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"description", "max_sessions"}),
+		}).Create(&model.Role{
+			Name:        role.Name,
+			Description: role.Description,
+			MaxSessions: int32(role.MaxSessions),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("role = ?", role.Name).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+
+		permissions := make([]model.Permission, 0, len(role.Permissions))
+		links := make([]model.RolePermission, 0, len(role.Permissions))
+		for _, permission := range role.Permissions {
+			permissions = append(permissions, model.Permission{Name: permission})
+			links = append(links, model.RolePermission{Role: role.Name, Permission: permission})
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&permissions).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+	})
+}
+
+// AssignRole выдаёт роль пользователю. Роль начнёт действовать со следующего выпуска токенов
+func AssignRole(sctx smart_context.ISmartContext, userId, role string) error {
+	if err := sctx.GetDB().Where("name = ?", role).First(&model.Role{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	if err := sctx.GetDB().Where("id = ?", userId).First(&model.User{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	return sctx.GetDB().Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserRole{UserID: userId, Role: role}).Error
+}
+
+// RoleAssigned — есть ли у роли хотя бы один пользователь
+func RoleAssigned(sctx smart_context.ISmartContext, role string) (bool, error) {
+	var count int64
+	err := sctx.GetDB().Model(&model.UserRole{}).Where("role = ?", role).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// RemoveRole забирает роль. false — у пользователя её не было
+func RemoveRole(sctx smart_context.ISmartContext, userId, role string) (bool, error) {
+	result := sctx.GetDB().Where("user_id = ? AND role = ?", userId, role).Delete(&model.UserRole{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// validName — имя попадает в claims scope/roles, где значения разделяются пробелами
+func validName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, " \t\n\"\\")
+}
@@ -0,0 +1,26 @@
+package access
+
+import "testing"
+
+func TestFilterScope(t *testing.T) {
+	granted := []string{"users:read", "sessions:revoke"}
+
+	cases := []struct {
+		requested string
+		want      string
+	}{
+		{"", ""},
+		{"openid email profile", "openid email profile"},
+		{"openid users:read", "openid users:read"},
+		{"users:write users:read", "users:read"},
+		// permission, которой ещё нет в таблице permissions, — тоже не выдаётся
+		{"billing:export", ""},
+		{"roles sessions:revoke offline", "roles sessions:revoke"},
+	}
+
+	for _, c := range cases {
+		if got := FilterScope(c.requested, granted); got != c.want {
+			t.Errorf("FilterScope(%q) = %q, want %q", c.requested, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package api_keys
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ключ имеет вид "tt3_<prefix>_<secret>": по prefix строка находится без перебора хэшей,
+// а по tt3_ ключ легко опознать в логах и сканерах секретов
+const (
+	keyMarker = "tt3_"
+	// lastUsedPrecision — чаще last_used_at не обновляется, чтобы не писать в базу на каждый запрос
+	lastUsedPrecision = time.Minute
+)
+
+var (
+	ErrKeyNotFound     = errors.New("api key not found")
+	ErrInvalidKey      = errors.New("invalid api key")
+	ErrKeyExpired      = errors.New("api key expired")
+	ErrKeyRevoked      = errors.New("api key revoked")
+	ErrScopeNotGranted = errors.New("scope is not granted to the key owner")
+)
+
+// NewKey — параметры нового ключа. Пустые Scopes — все permissions владельца и его роли
+type NewKey struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// KeyView — ключ в ответах API и CLI. Key заполнен только при создании и ротации
+type KeyView struct {
+	ID         string     `json:"id"`
+	Prefix     string     `json:"prefix"`
+	Name       string     `json:"name"`
+	OwnerID    string     `json:"owner_id"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Key        string     `json:"key,omitempty"`
+}
+
+// Create выпускает ключ владельцу. Scopes не могут выходить за permissions владельца
+func Create(sctx smart_context.ISmartContext, ownerId string, req NewKey) (*KeyView, error) {
+	userAccess, err := access.LoadUserAccess(sctx, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(userAccess.Permissions, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrScopeNotGranted, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expires_at is in the past", ErrInvalidKey)
+	}
+
+	key := model.APIKey{
+		Name:    strings.TrimSpace(req.Name),
+		OwnerID: ownerId,
+		Scopes:  strings.Join(req.Scopes, " "),
+	}
+	if req.ExpiresAt != nil {
+		key.ExpiresAt = *req.ExpiresAt
+	}
+
+	return insertKey(sctx, sctx.GetDB(), &key)
+}
+
+// Rotate выпускает вместо ключа новый с теми же владельцем, именем, scope и сроком, старый сразу отзывается.
+// ownerId пустой — без проверки владельца (CLI)
+func Rotate(sctx smart_context.ISmartContext, ownerId, keyId string) (*KeyView, error) {
+	var view *KeyView
+	err := sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		old, err := findOwned(tx, ownerId, keyId)
+		if err != nil {
+			return err
+		}
+		if !old.RevokedAt.IsZero() {
+			return ErrKeyRevoked
+		}
+
+		if err := revoke(tx, old.ID); err != nil {
+			return err
+		}
+
+		key := model.APIKey{
+			Name:      old.Name,
+			OwnerID:   old.OwnerID,
+			Scopes:    old.Scopes,
+			ExpiresAt: old.ExpiresAt,
+		}
+		view, err = insertKey(sctx, tx, &key)
+		return err
+	})
+	return view, err
+}
+
+// Revoke отзывает ключ. ownerId пустой — без проверки владельца (CLI)
+func Revoke(sctx smart_context.ISmartContext, ownerId, keyId string) error {
+	key, err := findOwned(sctx.GetDB(), ownerId, keyId)
+	if err != nil {
+		return err
+	}
+	if !key.RevokedAt.IsZero() {
+		return nil
+	}
+	return revoke(sctx.GetDB(), key.ID)
+}
+
+func List(sctx smart_context.ISmartContext, ownerId string) ([]KeyView, error) {
+	var keys []model.APIKey
+	if err := sctx.GetDB().Where("owner_id = ?", ownerId).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+
+	views := make([]KeyView, 0, len(keys))
+	for i := range keys {
+		views = append(views, newKeyView(&keys[i]))
+	}
+	return views, nil
+}
+
+// Authenticate проверяет ключ и возвращает claims, равноценные access-токену владельца:
+// sub — владелец, scope — scope ключа в пределах текущих permissions владельца.
+// Роли владельца получает только ключ без ограничения scope
+func Authenticate(sctx smart_context.ISmartContext, plain string) (*types.AccessClaims, error) {
+	prefix, ok := parsePrefix(plain)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	var key model.APIKey
+	err := sctx.GetDB().Where("prefix = ?", prefix).First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(helpers.HashToken(plain)), []byte(key.HashedKey)) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	now := time.Now()
+	if !key.RevokedAt.IsZero() {
+		return nil, ErrKeyRevoked
+	}
+	if !key.ExpiresAt.IsZero() && now.After(key.ExpiresAt) {
+		return nil, ErrKeyExpired
+	}
+
+	userAccess, err := access.LoadUserAccess(sctx, key.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &types.AccessClaims{
+		Subject:   key.OwnerID,
+		UserID:    key.OwnerID,
+		IssuedAt:  key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		APIKeyID:  key.ID,
+	}
+	if key.Scopes == "" {
+		claims.Scope = strings.Join(userAccess.Permissions, " ")
+		claims.Roles = userAccess.Roles
+	} else {
+		var scopes []string
+		for _, scope := range strings.Fields(key.Scopes) {
+			if slices.Contains(userAccess.Permissions, scope) {
+				scopes = append(scopes, scope)
+			}
+		}
+		claims.Scope = strings.Join(scopes, " ")
+	}
+
+	if now.Sub(key.LastUsedAt) > lastUsedPrecision {
+		err := sctx.GetDB().Model(&model.APIKey{}).Where("id = ?", key.ID).Update("last_used_at", now).Error
+		if err != nil {
+			sctx.Errorf("update api key last_used_at error: %v", err)
+		}
+	}
+
+	return claims, nil
+}
+
+func insertKey(sctx smart_context.ISmartContext, db *gorm.DB, key *model.APIKey) (*KeyView, error) {
+	prefix, err := helpers.GenerateRandomHex(6)
+	if err != nil {
+		return nil, fmt.Errorf("generateRandomHex error: %w", err)
+	}
+	secret, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return nil, fmt.Errorf("generateRandomHex error: %w", err)
+	}
+	plain := keyMarker + prefix + "_" + secret
+
+	key.Prefix = prefix
+	key.HashedKey = helpers.HashToken(plain)
+
+	// NULL, а не нулевое время, пока срока нет и ключ не использовался
+	omit := []string{"last_used_at", "revoked_at"}
+	if key.ExpiresAt.IsZero() {
+		omit = append(omit, "expires_at")
+	}
+	if err := db.Omit(omit...).Create(key).Error; err != nil {
+		return nil, fmt.Errorf("DB error: %w", err)
+	}
+
+	view := newKeyView(key)
+	view.Key = plain
+	return &view, nil
+}
+
+func findOwned(db *gorm.DB, ownerId, keyId string) (*model.APIKey, error) {
+	q := db.Where("id = ?", keyId)
+	if ownerId != "" {
+		q = q.Where("owner_id = ?", ownerId)
+	}
+
+	var key model.APIKey
+	err := q.First(&key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func revoke(db *gorm.DB, keyId string) error {
+	return db.Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", keyId).
+		Update("revoked_at", time.Now()).Error
+}
+
+func parsePrefix(plain string) (string, bool) {
+	rest, ok := strings.CutPrefix(plain, keyMarker)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	return prefix, ok && prefix != "" && secret != ""
+}
+
+func newKeyView(key *model.APIKey) KeyView {
+	return KeyView{
+		ID:         key.ID,
+		Prefix:     keyMarker + key.Prefix,
+		Name:       key.Name,
+		OwnerID:    key.OwnerID,
+		Scopes:     strings.Fields(key.Scopes),
+		ExpiresAt:  optionalTime(key.ExpiresAt),
+		LastUsedAt: optionalTime(key.LastUsedAt),
+		RevokedAt:  optionalTime(key.RevokedAt),
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
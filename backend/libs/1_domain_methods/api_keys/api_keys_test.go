@@ -0,0 +1,208 @@
+package api_keys
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/test_db"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const testOwnerID = "user-1"
+
+// newTestContext создаёт владельца с ролью reader: permissions users:read и audit:read
+func newTestContext(t *testing.T) (smart_context.ISmartContext, *gorm.DB) {
+	t.Helper()
+
+	db := test_db.Open(t, "users", "roles", "permissions", "role_permissions", "user_roles", "api_keys")
+	for _, statement := range []string{
+		`INSERT INTO users (id, email, password) VALUES ('user-1', 'alice@example.com', 'hash'), ('user-2', 'bob@example.com', 'hash')`,
+		`INSERT INTO roles (name) VALUES ('reader')`,
+		`INSERT INTO permissions (name) VALUES ('users:read'), ('audit:read'), ('users:write')`,
+		`INSERT INTO role_permissions (role, permission) VALUES ('reader', 'users:read'), ('reader', 'audit:read')`,
+		`INSERT INTO user_roles (user_id, role) VALUES ('user-1', 'reader')`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return smart_context.NewSmartContext().WithDB(db), db
+}
+
+func TestParsePrefix(t *testing.T) {
+	tests := []struct {
+		plain  string
+		prefix string
+		ok     bool
+	}{
+		{"tt3_a1b2c3_secret", "a1b2c3", true},
+		{"tt3_a1b2c3_secret_with_underscores", "a1b2c3", true},
+		{"tt3_a1b2c3_", "", false},
+		{"tt3__secret", "", false},
+		{"tt3_a1b2c3", "", false},
+		{"xx3_a1b2c3_secret", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		prefix, ok := parsePrefix(tt.plain)
+		if ok != tt.ok || (ok && prefix != tt.prefix) {
+			t.Errorf("parsePrefix(%q) = %q, %v, want %q, %v", tt.plain, prefix, ok, tt.prefix, tt.ok)
+		}
+	}
+}
+
+func TestCreateRejects(t *testing.T) {
+	sctx, _ := newTestContext(t)
+
+	// permission существует, но у владельца её нет
+	if _, err := Create(sctx, testOwnerID, NewKey{Name: "ci", Scopes: []string{"users:read", "users:write"}}); !errors.Is(err, ErrScopeNotGranted) {
+		t.Errorf("Create(users:write) error = %v, want %v", err, ErrScopeNotGranted)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	if _, err := Create(sctx, testOwnerID, NewKey{Name: "ci", ExpiresAt: &past}); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Create(expired) error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	sctx, db := newTestContext(t)
+
+	scoped, err := Create(sctx, testOwnerID, NewKey{Name: "ci", Scopes: []string{"users:read"}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if !strings.HasPrefix(scoped.Key, scoped.Prefix+"_") {
+		t.Errorf("key %q does not start with prefix %q", scoped.Key, scoped.Prefix)
+	}
+
+	claims, err := Authenticate(sctx, scoped.Key)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	// ключ с ограниченным scope не получает ролей владельца
+	if claims.GetUserID() != testOwnerID || claims.Scope != "users:read" || len(claims.Roles) != 0 || claims.APIKeyID != scoped.ID {
+		t.Errorf("Authenticate() = %+v", claims)
+	}
+
+	unscoped, err := Create(sctx, testOwnerID, NewKey{Name: "cli"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err = Authenticate(sctx, unscoped.Key)
+	if err != nil {
+		t.Fatalf("Authenticate(unscoped) error = %v", err)
+	}
+	if claims.Scope != "audit:read users:read" || !slices.Equal(claims.Roles, []string{"reader"}) {
+		t.Errorf("Authenticate(unscoped) scope = %q, roles = %v", claims.Scope, claims.Roles)
+	}
+
+	// scope ключа сужается вместе с permissions владельца
+	if err := db.Exec(`DELETE FROM role_permissions WHERE permission = 'users:read'`).Error; err != nil {
+		t.Fatal(err)
+	}
+	if claims, err = Authenticate(sctx, scoped.Key); err != nil || claims.Scope != "" {
+		t.Errorf("Authenticate() after permission removed = %+v, %v, want empty scope", claims, err)
+	}
+}
+
+func TestAuthenticateRejects(t *testing.T) {
+	sctx, db := newTestContext(t)
+
+	create := func() *KeyView {
+		t.Helper()
+		key, err := Create(sctx, testOwnerID, NewKey{Name: "ci"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+
+	key := create()
+	wrongSecret := key.Key[:len(key.Key)-1] + "0"
+	if wrongSecret == key.Key {
+		wrongSecret = key.Key[:len(key.Key)-1] + "1"
+	}
+	if _, err := Authenticate(sctx, wrongSecret); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate(wrong secret) error = %v, want %v", err, ErrInvalidKey)
+	}
+	if _, err := Authenticate(sctx, "tt3_000000_secret"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Authenticate(unknown prefix) error = %v, want %v", err, ErrKeyNotFound)
+	}
+	if _, err := Authenticate(sctx, "Bearer something"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate(malformed) error = %v, want %v", err, ErrInvalidKey)
+	}
+
+	if err := Revoke(sctx, testOwnerID, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Authenticate(sctx, key.Key); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Authenticate(revoked) error = %v, want %v", err, ErrKeyRevoked)
+	}
+
+	expired := create()
+	if err := db.Model(&model.APIKey{}).Where("id = ?", expired.ID).Update("expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Authenticate(sctx, expired.Key); !errors.Is(err, ErrKeyExpired) {
+		t.Errorf("Authenticate(expired) error = %v, want %v", err, ErrKeyExpired)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	sctx, _ := newTestContext(t)
+
+	old, err := Create(sctx, testOwnerID, NewKey{Name: "ci", Scopes: []string{"audit:read"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// чужой ключ не ротируется
+	if _, err := Rotate(sctx, "user-2", old.ID); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Rotate(another owner) error = %v, want %v", err, ErrKeyNotFound)
+	}
+
+	rotated, err := Rotate(sctx, testOwnerID, old.ID)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotated.ID == old.ID || rotated.Name != "ci" || !slices.Equal(rotated.Scopes, []string{"audit:read"}) {
+		t.Errorf("Rotate() = %+v", rotated)
+	}
+	if _, err := Authenticate(sctx, old.Key); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Authenticate(old) error = %v, want %v", err, ErrKeyRevoked)
+	}
+	if _, err := Authenticate(sctx, rotated.Key); err != nil {
+		t.Errorf("Authenticate(rotated) error = %v", err)
+	}
+
+	if _, err := Rotate(sctx, testOwnerID, old.ID); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Rotate(revoked) error = %v, want %v", err, ErrKeyRevoked)
+	}
+}
+
+// ключ без замены не отзывается: отзыв и выпуск нового в одной транзакции
+func TestRotateRollsBack(t *testing.T) {
+	sctx, db := newTestContext(t)
+
+	old, err := Create(sctx, testOwnerID, NewKey{Name: "ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(`CREATE TRIGGER api_keys_insert_fails BEFORE INSERT ON api_keys BEGIN SELECT RAISE(ABORT, 'insert failed'); END`).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Rotate(sctx, testOwnerID, old.ID); err == nil {
+		t.Fatal("Rotate() succeeded with a failing insert")
+	}
+	if _, err := Authenticate(sctx, old.Key); err != nil {
+		t.Errorf("Authenticate(old) after failed rotation error = %v", err)
+	}
+}
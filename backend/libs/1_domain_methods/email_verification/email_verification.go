@@ -0,0 +1,264 @@
+package email_verification
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTokenInvalid     = errors.New("invalid or expired email verification token")
+	ErrEmailNotVerified = errors.New("email is not verified")
+	ErrAlreadyVerified  = errors.New("email is already verified")
+	ErrEmailTaken       = errors.New("email is already in use")
+)
+
+type Config struct {
+	TokenTTL time.Duration
+	// VerifyURL — страница подтверждения, токен добавляется параметром token. Пустой — в письме только токен
+	VerifyURL string
+	// Required — вход только с подтверждённым email
+	Required bool
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	return Config{
+		TokenTTL:  time.Duration(env_vars.GetEnvAsInt(sctx, "EMAIL_VERIFICATION_TOKEN_TTL_SECONDS", 86400)) * time.Second,
+		VerifyURL: os.Getenv("EMAIL_VERIFICATION_URL"),
+		Required:  os.Getenv("EMAIL_VERIFICATION_REQUIRED") == "true",
+	}
+}
+
+// Result — подтверждённый адрес. PreviousEmail заполнен, если подтверждение завершило смену email
+type Result struct {
+	UserID        string
+	Email         string
+	PreviousEmail string
+}
+
+// CheckLogin применяет политику входа: при cfg.Required пользователь без подтверждённого email не входит
+func CheckLogin(cfg Config, user *model.User) error {
+	if cfg.Required && user.EmailVerifiedAt.IsZero() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// CheckLoginByID — CheckLogin для входа, где известен только id пользователя
+func CheckLoginByID(sctx smart_context.ISmartContext, cfg Config, userId string) error {
+	if !cfg.Required {
+		return nil
+	}
+
+	var user model.User
+	if err := sctx.GetDB().Where("id = ?", userId).First(&user).Error; err != nil {
+		return err
+	}
+	return CheckLogin(cfg, &user)
+}
+
+// Resend отправляет письмо для подтверждения текущего email. Для неизвестного email ничего не делает
+// и возвращает пустой userId — ответ клиенту от этого не зависит
+func Resend(sctx smart_context.ISmartContext, cfg Config, email string) (string, error) {
+	var user model.User
+	err := sctx.GetDB().Where("lower(email) = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !user.EmailVerifiedAt.IsZero() {
+		return user.ID, ErrAlreadyVerified
+	}
+
+	token, err := issueToken(sctx, cfg, user.ID, user.Email)
+	if err != nil {
+		return user.ID, err
+	}
+	return user.ID, send(sctx, verifyMessage(cfg, user.Email, token))
+}
+
+// RequestChange начинает смену email: письмо со ссылкой уходит на новый адрес, а старый остаётся
+// в аккаунте, пока новый не подтверждён. На старый адрес уходит уведомление
+func RequestChange(sctx smart_context.ISmartContext, cfg Config, user *model.User, newEmail string) error {
+	if newEmail == normalize(user.Email) {
+		return ErrAlreadyVerified
+	}
+	if err := checkEmailFree(sctx.GetDB(), user.ID, newEmail); err != nil {
+		return err
+	}
+
+	token, err := issueToken(sctx, cfg, user.ID, newEmail)
+	if err != nil {
+		return err
+	}
+	if err := send(sctx, verifyMessage(cfg, newEmail, token)); err != nil {
+		return err
+	}
+
+	if err := send(sctx, changeNoticeMessage(user.Email, newEmail)); err != nil {
+		sctx.Errorf("send email change notice error: %v", err)
+	}
+	return nil
+}
+
+// Verify подтверждает адрес из токена. Если это новый адрес, он становится email пользователя.
+// Остальные неиспользованные токены пользователя гасятся
+func Verify(sctx smart_context.ISmartContext, token string) (*Result, error) {
+	var verification model.EmailVerificationToken
+	err := sctx.GetDB().Where("token_hash = ?", helpers.HashToken(token)).First(&verification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !verification.UsedAt.IsZero() || time.Now().After(verification.ExpiresAt) {
+		return nil, ErrTokenInvalid
+	}
+
+	result := &Result{UserID: verification.UserID, Email: verification.Email}
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// условие на used_at — чтобы из двух одновременных запросов с одним токеном прошёл один
+		marked := tx.Model(&model.EmailVerificationToken{}).
+			Where("id = ? AND used_at IS NULL", verification.ID).
+			Update("used_at", now)
+		if marked.Error != nil {
+			return marked.Error
+		}
+		if marked.RowsAffected == 0 {
+			return ErrTokenInvalid
+		}
+
+		var user model.User
+		if err := tx.Where("id = ?", verification.UserID).First(&user).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]any{"email_verified_at": now}
+		if normalize(user.Email) != verification.Email {
+			// адрес мог занять другой аккаунт, пока письмо шло
+			if err := checkEmailFree(tx, user.ID, verification.Email); err != nil {
+				return err
+			}
+			updates["email"] = verification.Email
+			updates["updated_at"] = now
+			result.PreviousEmail = user.Email
+		}
+		if err := tx.Model(&model.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.EmailVerificationToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// issueToken выпускает токен подтверждения email. Ссылки из прошлых писем перестают работать
+func issueToken(sctx smart_context.ISmartContext, cfg Config, userId, email string) (string, error) {
+	token, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.EmailVerificationToken{}).
+			Where("user_id = ? AND used_at IS NULL", userId).
+			Update("used_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		verification := model.EmailVerificationToken{
+			TokenHash: helpers.HashToken(token),
+			UserID:    userId,
+			Email:     normalize(email),
+			ExpiresAt: time.Now().Add(cfg.TokenTTL),
+		}
+		return tx.Omit("UsedAt").Create(&verification).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func checkEmailFree(db *gorm.DB, userId, email string) error {
+	var count int64
+	err := db.Model(&model.User{}).Where("lower(email) = ? AND id <> ?", email, userId).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+func send(sctx smart_context.ISmartContext, message types.MailMessage) error {
+	mailer := sctx.GetMailer()
+	if mailer == nil {
+		return errors.New("mailer is not configured")
+	}
+	return mailer.Send(sctx.GetContext(), message)
+}
+
+func verifyMessage(cfg Config, email, token string) types.MailMessage {
+	link := token
+	if cfg.VerifyURL != "" {
+		if verifyURL, err := url.Parse(cfg.VerifyURL); err == nil {
+			query := verifyURL.Query()
+			query.Set("token", token)
+			verifyURL.RawQuery = query.Encode()
+			link = verifyURL.String()
+		}
+	}
+
+	return types.MailMessage{
+		To:      email,
+		Subject: "Confirm your email address",
+		Text: fmt.Sprintf("To confirm that %s is your email address, use this within %s:\n%s\n\n"+
+			"If you didn't sign up or change your email, ignore this email.\n",
+			email, validity(cfg.TokenTTL), link),
+	}
+}
+
+func validity(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
+
+func normalize(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func changeNoticeMessage(oldEmail, newEmail string) types.MailMessage {
+	return types.MailMessage{
+		To:      oldEmail,
+		Subject: "Email change requested",
+		Text: fmt.Sprintf("Someone requested to change the email address of your account to %s.\n\n"+
+			"The change takes effect only after the new address is confirmed. "+
+			"If it wasn't you, reset your password.\n", newEmail),
+	}
+}
@@ -0,0 +1,193 @@
+package email_verification
+
+import (
+	"errors"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/test_db"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	testUserID = "user-1"
+	testEmail  = "alice@example.com"
+)
+
+var testConfig = Config{TokenTTL: time.Hour, VerifyURL: "https://app.example.com/verify"}
+
+// lastToken достаёт токен из ссылки в последнем письме на адрес to
+func lastToken(t *testing.T, mailer *test_db.Mailer, to string) string {
+	t.Helper()
+	return mailer.LastLink(t, to).Query().Get("token")
+}
+
+func newTestContext(t *testing.T) (smart_context.ISmartContext, *gorm.DB, *test_db.Mailer) {
+	t.Helper()
+
+	db := test_db.Open(t, "users", "email_verification_tokens")
+	if err := db.Omit("EmailVerifiedAt").Create(&model.User{ID: testUserID, Email: testEmail, Password: "hash"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	mailer := &test_db.Mailer{}
+	return smart_context.NewSmartContext().WithDB(db).WithMailer(mailer), db, mailer
+}
+
+func loadUser(t *testing.T, db *gorm.DB, userId string) model.User {
+	t.Helper()
+
+	var user model.User
+	if err := db.Where("id = ?", userId).First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestVerify(t *testing.T) {
+	sctx, db, mailer := newTestContext(t)
+
+	if _, err := Resend(sctx, testConfig, testEmail); err != nil {
+		t.Fatalf("Resend() error = %v", err)
+	}
+	token := lastToken(t, mailer, testEmail)
+
+	result, err := Verify(sctx, token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if *result != (Result{UserID: testUserID, Email: testEmail}) {
+		t.Errorf("Verify() = %+v", result)
+	}
+	if loadUser(t, db, testUserID).EmailVerifiedAt.IsZero() {
+		t.Error("email_verified_at is not set")
+	}
+
+	// токен одноразовый, а подтверждённому адресу новое письмо не нужно
+	if _, err := Verify(sctx, token); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("second Verify() error = %v, want %v", err, ErrTokenInvalid)
+	}
+	if _, err := Resend(sctx, testConfig, testEmail); !errors.Is(err, ErrAlreadyVerified) {
+		t.Errorf("Resend() error = %v, want %v", err, ErrAlreadyVerified)
+	}
+}
+
+func TestVerifyRejectsExpiredToken(t *testing.T) {
+	sctx, db, mailer := newTestContext(t)
+
+	if _, err := Resend(sctx, testConfig, testEmail); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&model.EmailVerificationToken{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Verify(sctx, lastToken(t, mailer, testEmail)); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrTokenInvalid)
+	}
+	if !loadUser(t, db, testUserID).EmailVerifiedAt.IsZero() {
+		t.Error("email verified by an expired token")
+	}
+}
+
+func TestIssueTokenInvalidatesPrevious(t *testing.T) {
+	sctx, _, _ := newTestContext(t)
+
+	first, err := issueToken(sctx, testConfig, testUserID, testEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := issueToken(sctx, testConfig, testUserID, " Alice@Example.com ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ссылка из прошлого письма больше не работает
+	if _, err := Verify(sctx, first); !errors.Is(err, ErrTokenInvalid) {
+		t.Errorf("Verify(first) error = %v, want %v", err, ErrTokenInvalid)
+	}
+	result, err := Verify(sctx, second)
+	if err != nil {
+		t.Fatalf("Verify(second) error = %v", err)
+	}
+	// адрес сохраняется нормализованным
+	if result.Email != testEmail {
+		t.Errorf("Verify(second).Email = %q, want %q", result.Email, testEmail)
+	}
+}
+
+func TestRequestChange(t *testing.T) {
+	sctx, db, mailer := newTestContext(t)
+	user := loadUser(t, db, testUserID)
+
+	if err := RequestChange(sctx, testConfig, &user, "alice@new.example.com"); err != nil {
+		t.Fatalf("RequestChange() error = %v", err)
+	}
+	// старый адрес получает уведомление, а email не меняется до подтверждения
+	if len(mailer.Sent) != 2 || mailer.Sent[1].To != testEmail {
+		t.Fatalf("sent = %+v, want a link to the new address and a notice to the old one", mailer.Sent)
+	}
+	if got := loadUser(t, db, testUserID).Email; got != testEmail {
+		t.Fatalf("email changed to %q before confirmation", got)
+	}
+
+	result, err := Verify(sctx, lastToken(t, mailer, "alice@new.example.com"))
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if result.Email != "alice@new.example.com" || result.PreviousEmail != testEmail {
+		t.Errorf("Verify() = %+v", result)
+	}
+	if got := loadUser(t, db, testUserID).Email; got != "alice@new.example.com" {
+		t.Errorf("email = %q after confirmation", got)
+	}
+}
+
+func TestRequestChangeRejects(t *testing.T) {
+	sctx, db, mailer := newTestContext(t)
+	if err := db.Create(&model.User{ID: "user-2", Email: "Bob@Example.com", Password: "hash"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	user := loadUser(t, db, testUserID)
+
+	if err := RequestChange(sctx, testConfig, &user, testEmail); !errors.Is(err, ErrAlreadyVerified) {
+		t.Errorf("RequestChange(same email) error = %v, want %v", err, ErrAlreadyVerified)
+	}
+	if err := RequestChange(sctx, testConfig, &user, "bob@example.com"); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("RequestChange(taken email) error = %v, want %v", err, ErrEmailTaken)
+	}
+	if len(mailer.Sent) != 0 {
+		t.Errorf("sent %d messages, want none", len(mailer.Sent))
+	}
+}
+
+// адрес может занять другой аккаунт, пока письмо со ссылкой идёт: подтверждение должно это заметить
+func TestVerifyRechecksEmailIsFree(t *testing.T) {
+	sctx, db, mailer := newTestContext(t)
+	user := loadUser(t, db, testUserID)
+
+	if err := RequestChange(sctx, testConfig, &user, "carol@example.com"); err != nil {
+		t.Fatalf("RequestChange() error = %v", err)
+	}
+	if err := db.Create(&model.User{ID: "user-3", Email: "Carol@example.com", Password: "hash"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	token := lastToken(t, mailer, "carol@example.com")
+	if _, err := Verify(sctx, token); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("Verify() error = %v, want %v", err, ErrEmailTaken)
+	}
+	if got := loadUser(t, db, testUserID).Email; got != testEmail {
+		t.Errorf("email changed to %q", got)
+	}
+
+	// транзакция откатилась: токен не погашен и сработает, когда адрес освободится
+	if err := db.Where("id = ?", "user-3").Delete(&model.User{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(sctx, token); err != nil {
+		t.Errorf("Verify() after the address was freed error = %v", err)
+	}
+}
@@ -0,0 +1,348 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/1_domain_methods/handlers/auth"
+	"test-task3/libs/1_domain_methods/handlers/oauth"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/3_infrastructure/auditor"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type auditEventsResponse struct {
+	Events     []model.AuditEvent `json:"events"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+type revokeUserTokensRequest struct {
+	Before *time.Time `json:"before"`
+}
+
+type sessionLimitRequest struct {
+	MaxSessions *int `json:"max_sessions"`
+}
+
+type revokeTokenRequest struct {
+	Token string `json:"token"`
+}
+
+type rotateSecretRequest struct {
+	OverlapSeconds *int `json:"overlap_seconds"`
+}
+
+type unlockRequest struct {
+	UserID string `json:"user_id"`
+	IP     string `json:"ip"`
+}
+
+func AdminRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	oauthCfg := oauth.LoadConfig(sctx)
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(middlewares.RequireAdmin(sctx, access.RoleAdmin))
+
+		r.Handle("/metrics", expvar.Handler())
+
+		r.Get("/audit-events", func(w http.ResponseWriter, r *http.Request) {
+			filter, err := parseAuditFilter(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			events, nextCursor, err := auditor.Query(sctx, filter)
+			if errors.Is(err, auditor.ErrInvalidCursor) {
+				http.Error(w, "invalid cursor", http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				sctx.Errorf("query audit events error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, auditEventsResponse{
+				Events:     events,
+				NextCursor: nextCursor,
+			})
+		})
+
+		r.Post("/users/{userId}/revoke-tokens", func(w http.ResponseWriter, r *http.Request) {
+			userId := chi.URLParam(r, "userId")
+
+			var req revokeUserTokensRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			before := time.Now()
+			if req.Before != nil {
+				before = *req.Before
+			}
+
+			if err := auth.RevokeAllUserTokens(sctx, r, userId, before, "admin request"); err != nil {
+				sctx.Errorf("revoke user tokens error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Put("/users/{userId}/session-limit", func(w http.ResponseWriter, r *http.Request) {
+			userId := chi.URLParam(r, "userId")
+
+			var req sessionLimitRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MaxSessions == nil {
+				http.Error(w, "missing max_sessions", http.StatusBadRequest)
+				return
+			}
+
+			err := sessions.SetUserMaxSessions(sctx, userId, *req.MaxSessions)
+			if errors.Is(err, access.ErrUserNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				sctx.Errorf("set session limit error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			sctx.Warnf("Session limit of user %s set to %d by admin", userId, *req.MaxSessions)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Post("/tokens/revoke", func(w http.ResponseWriter, r *http.Request) {
+			var req revokeTokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+				http.Error(w, "missing token", http.StatusBadRequest)
+				return
+			}
+
+			claims, err := sctx.GetTokenService().ParseAccessToken(req.Token)
+			if err != nil && !errors.Is(err, types.ErrTokenExpired) {
+				http.Error(w, "invalid token: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if claims.ID == "" {
+				http.Error(w, "token has no jti, revoke all user tokens instead", http.StatusBadRequest)
+				return
+			}
+
+			if err := sctx.GetTokenService().Revoke(r.Context(), claims, "admin request"); err != nil {
+				sctx.Errorf("revoke token error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    claims.GetUserID(),
+				EventType: types.AuditTokenRevoked,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"jti": claims.ID, "reason": "admin request"},
+			})
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Get("/lockouts", func(w http.ResponseWriter, r *http.Request) {
+			locks, err := lockout.ListActive(sctx)
+			if err != nil {
+				sctx.Errorf("list lockouts error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, locks)
+		})
+
+		r.Post("/lockouts/unlock", func(w http.ResponseWriter, r *http.Request) {
+			var req unlockRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+			if req.UserID == "" && req.IP == "" {
+				http.Error(w, "missing user_id or ip", http.StatusBadRequest)
+				return
+			}
+
+			subjects := map[string]string{
+				lockout.SubjectUser: req.UserID,
+				lockout.SubjectIP:   req.IP,
+			}
+
+			unlocked := false
+			for subjectType, subject := range subjects {
+				if subject == "" {
+					continue
+				}
+
+				ok, err := lockout.Unlock(sctx, subjectType, subject)
+				if err != nil {
+					sctx.Errorf("unlock %s error: %v", subjectType, err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+				if !ok {
+					continue
+				}
+
+				unlocked = true
+				sctx.Warnf("Lockout of %s %s removed by admin", subjectType, subject)
+				helpers.Audit(sctx, r, types.AuditEvent{
+					UserID:    req.UserID,
+					EventType: types.AuditAccountUnlocked,
+					Outcome:   types.AuditSuccess,
+					Details: types.Fields{
+						"subject_type": subjectType,
+						"subject":      subject,
+						"by":           "admin",
+					},
+				})
+			}
+
+			helpers.WriteJSON(w, map[string]bool{"unlocked": unlocked})
+		})
+
+		r.Get("/clients", func(w http.ResponseWriter, r *http.Request) {
+			clients, err := oauth.ListClients(sctx)
+			if err != nil {
+				sctx.Errorf("list clients error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, clients)
+		})
+
+		r.Post("/clients", func(w http.ResponseWriter, r *http.Request) {
+			var req oauth.NewClient
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			client, err := oauth.CreateClient(sctx, req)
+			if errors.Is(err, oauth.ErrInvalidClient) || errors.Is(err, oauth.ErrInvalidRedirectURI) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				sctx.Errorf("create client error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			sctx.Infof("Client %s created by admin", client.ID)
+			helpers.WriteJSON(w, client)
+		})
+
+		r.Post("/clients/{clientId}/rotate-secret", func(w http.ResponseWriter, r *http.Request) {
+			var req rotateSecretRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			overlap := oauthCfg.SecretOverlap
+			if req.OverlapSeconds != nil {
+				if *req.OverlapSeconds < 0 {
+					http.Error(w, "invalid overlap_seconds", http.StatusBadRequest)
+					return
+				}
+				overlap = time.Duration(*req.OverlapSeconds) * time.Second
+			}
+
+			client, err := oauth.RotateClientSecret(sctx, chi.URLParam(r, "clientId"), overlap)
+			if errors.Is(err, oauth.ErrClientNotFound) {
+				http.Error(w, "client not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, oauth.ErrPublicClient) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				sctx.Errorf("rotate client secret error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			sctx.Warnf("Secret of client %s rotated by admin, previous secret valid for %s", client.ID, overlap)
+			helpers.WriteJSON(w, client)
+		})
+
+		r.Delete("/clients/{clientId}", func(w http.ResponseWriter, r *http.Request) {
+			clientId := chi.URLParam(r, "clientId")
+
+			err := oauth.DeleteClient(sctx, clientId)
+			if errors.Is(err, oauth.ErrClientNotFound) {
+				http.Error(w, "client not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				sctx.Errorf("delete client error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			sctx.Warnf("Client %s deleted by admin", clientId)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		roleRoutes(r, sctx)
+	})
+}
+
+func parseAuditFilter(r *http.Request) (auditor.Filter, error) {
+	query := r.URL.Query()
+
+	filter := auditor.Filter{
+		UserID:    query.Get("user_id"),
+		EventType: query.Get("event_type"),
+		Outcome:   query.Get("outcome"),
+		IPAddress: query.Get("ip"),
+		Cursor:    query.Get("cursor"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", limit)
+		}
+		filter.Limit = value
+	}
+
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: expected RFC3339 time", name)
+		}
+		*target = parsed
+	}
+
+	return filter, nil
+}
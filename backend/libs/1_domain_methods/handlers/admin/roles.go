@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type saveRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	MaxSessions int      `json:"max_sessions"`
+}
+
+type assignRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func roleRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	r.Get("/roles", func(w http.ResponseWriter, r *http.Request) {
+		roles, err := access.ListRoles(sctx)
+		if err != nil {
+			sctx.Errorf("list roles error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		helpers.WriteJSON(w, roles)
+	})
+
+	r.Put("/roles/{role}", func(w http.ResponseWriter, r *http.Request) {
+		var req saveRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		role := access.RoleView{
+			Name:        chi.URLParam(r, "role"),
+			Description: req.Description,
+			Permissions: req.Permissions,
+			MaxSessions: req.MaxSessions,
+		}
+
+		err := access.SaveRole(sctx, role)
+		if errors.Is(err, access.ErrInvalidName) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			sctx.Errorf("save role error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		sctx.Warnf("Role %s saved by admin with permissions %v and session limit %d", role.Name, role.Permissions, role.MaxSessions)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r.Get("/users/{userId}/roles", func(w http.ResponseWriter, r *http.Request) {
+		userAccess, err := access.LoadUserAccess(sctx, chi.URLParam(r, "userId"))
+		if err != nil {
+			sctx.Errorf("load user access error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		helpers.WriteJSON(w, userAccess)
+	})
+
+	r.Post("/users/{userId}/roles", func(w http.ResponseWriter, r *http.Request) {
+		userId := chi.URLParam(r, "userId")
+
+		var req assignRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Role == "" {
+			http.Error(w, "missing role", http.StatusBadRequest)
+			return
+		}
+
+		err := access.AssignRole(sctx, userId, req.Role)
+		if errors.Is(err, access.ErrRoleNotFound) || errors.Is(err, access.ErrUserNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			sctx.Errorf("assign role error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditRoleAssigned,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"role": req.Role, "by": "admin"},
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r.Delete("/users/{userId}/roles/{role}", func(w http.ResponseWriter, r *http.Request) {
+		userId, role := chi.URLParam(r, "userId"), chi.URLParam(r, "role")
+
+		removed, err := access.RemoveRole(sctx, userId, role)
+		if err != nil {
+			sctx.Errorf("remove role error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if !removed {
+			http.Error(w, "user has no such role", http.StatusNotFound)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditRoleRemoved,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"role": role, "by": "admin"},
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
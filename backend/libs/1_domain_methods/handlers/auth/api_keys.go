@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"test-task3/libs/1_domain_methods/api_keys"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func apiKeyRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	r.Route("/auth/api-keys", func(r chi.Router) {
+		r.Use(middlewares.Authenticate(sctx))
+		r.Use(requireUserToken)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+
+			keys, err := api_keys.List(reqCtx, reqCtx.GetAccessClaims().GetUserID())
+			if err != nil {
+				reqCtx.Errorf("list api keys error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, keys)
+		})
+
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			userId := reqCtx.GetAccessClaims().GetUserID()
+
+			var req api_keys.NewKey
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			key, err := api_keys.Create(reqCtx, userId, req)
+			if errors.Is(err, api_keys.ErrScopeNotGranted) || errors.Is(err, api_keys.ErrInvalidKey) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err != nil {
+				reqCtx.Errorf("create api key error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			auditAPIKey(reqCtx, r, types.AuditAPIKeyCreated, userId, key.ID)
+			helpers.WriteJSON(w, key)
+		})
+
+		r.Post("/{keyId}/rotate", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			userId := reqCtx.GetAccessClaims().GetUserID()
+
+			keyId := chi.URLParam(r, "keyId")
+
+			key, err := api_keys.Rotate(reqCtx, userId, keyId)
+			if errors.Is(err, api_keys.ErrKeyNotFound) {
+				http.Error(w, "api key not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, api_keys.ErrKeyRevoked) {
+				http.Error(w, "api key is revoked", http.StatusConflict)
+				return
+			}
+			if err != nil {
+				reqCtx.Errorf("rotate api key error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			auditAPIKey(reqCtx, r, types.AuditAPIKeyRotated, userId, keyId)
+			helpers.WriteJSON(w, key)
+		})
+
+		r.Delete("/{keyId}", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			userId := reqCtx.GetAccessClaims().GetUserID()
+			keyId := chi.URLParam(r, "keyId")
+
+			err := api_keys.Revoke(reqCtx, userId, keyId)
+			if errors.Is(err, api_keys.ErrKeyNotFound) {
+				http.Error(w, "api key not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				reqCtx.Errorf("revoke api key error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			auditAPIKey(reqCtx, r, types.AuditAPIKeyRevoked, userId, keyId)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+}
+
+// requireUserToken — учётными данными (API-ключи, второй фактор, passkeys, сессии) пользователь управляет
+// только по access-токену собственного входа: ни API-ключ, ни токен, выданный OAuth2-клиенту — сервису
+// или стороннему приложению от имени пользователя, — для этого не подходят
+func requireUserToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx, _ := smart_context.FromContext(r.Context())
+		claims := reqCtx.GetAccessClaims()
+
+		if claims.APIKeyID != "" || claims.ClientID != "" {
+			http.Error(w, "this endpoint requires a user access token", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func auditAPIKey(sctx smart_context.ISmartContext, r *http.Request, eventType types.AuditEventType, userId, keyId string) {
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: eventType,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"api_key_id": keyId},
+	})
+}
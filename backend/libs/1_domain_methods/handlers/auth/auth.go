@@ -1,69 +1,34 @@
 package auth
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"test-task3/libs/1_domain_methods/helpers"
-	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/1_domain_methods/sessions"
 	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
 
 	"github.com/go-chi/chi/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func AuthRoutes(r chi.Router, sctx smart_context.ISmartContext) {
-	r.Post("/auth", func(w http.ResponseWriter, r *http.Request) {
-		userId := r.URL.Query().Get("userId")
-		if userId == "" {
-			http.Error(w, "missing userId", http.StatusBadRequest)
-			return
-		}
-
-		clientIP := helpers.GetClientIP(r)
-
-		accessToken, err := helpers.GenerateJWT(sctx, userId, clientIP)
-		if err != nil {
-			sctx.Errorf("generateJWT error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
-		}
-
-		// Refresh-токен base64
-		refreshPlain, err := helpers.GenerateRandomBase64(32)
-		if err != nil {
-			sctx.Errorf("generateRandomBase64 error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
-		}
-
-		hashedToken, err := bcrypt.GenerateFromPassword([]byte(refreshPlain), bcrypt.DefaultCost)
-		if err != nil {
-			sctx.Errorf("bcrypt error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
-		}
-
-		newRefresh := model.RefreshToken{
-			UserID:      userId,
-			HashedToken: string(hashedToken),
-			IPAddress:   clientIP,
-			Used:        false,
-		}
-
-		if err := sctx.GetDB().Save(&newRefresh).Error; err != nil {
-			sctx.Errorf("DB error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
-		}
-
-		resp := map[string]string{
-			"access_token":  accessToken,
-			"refresh_token": refreshPlain,
-		}
-		writeJSON(w, resp)
+	refreshLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_refresh",
+		PerIP:   "30/m",
+		PerUser: "10/m",
+		// только подпись: отзыв и привязку к IP проверит сам хэндлер, иначе решение попало бы в метрики дважды,
+		// а при отказе по IP лимит по пользователю не применялся бы
+		UserID: func(r *http.Request) string {
+			claims, err := sctx.GetTokenService().ParseAccessToken(r.Header.Get("X-Access-Token"))
+			if claims == nil || (err != nil && !errors.Is(err, types.ErrTokenExpired)) {
+				return ""
+			}
+			return claims.GetUserID()
+		},
 	})
 
-	r.Post("/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+	r.With(refreshLimit).Post("/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
 		accessToken := r.Header.Get("X-Access-Token")
 		refreshPlain := r.Header.Get("X-Refresh-Token")
 
@@ -72,87 +37,55 @@ func AuthRoutes(r chi.Router, sctx smart_context.ISmartContext) {
 			return
 		}
 
-		userId, jwtErr := helpers.ParseJWT(sctx, accessToken)
-		if jwtErr != nil {
+		// истёкший access-токен — обычная причина для refresh, поэтому ErrTokenExpired здесь не ошибка
+		claims, jwtErr := helpers.ParseJWT(sctx, r, accessToken)
+		if jwtErr != nil && !errors.Is(jwtErr, types.ErrTokenExpired) {
 			sctx.Errorf("parseJWT error: %v", jwtErr)
-			http.Error(w, "invalid access token", http.StatusUnauthorized)
+			auditRefreshFailed(sctx, r, "", "invalid access token: "+jwtErr.Error())
+			helpers.WriteTokenError(w, jwtErr)
 			return
 		}
+		userId := claims.GetUserID()
 
-		var ref model.RefreshToken
-		if err := sctx.GetDB().Where("user_id = ? AND used = false", userId).Last(&ref).Error; err != nil {
+		ref, err := FindRefreshToken(sctx, userId, refreshPlain)
+		if errors.Is(err, ErrRefreshTokenNotFound) {
 			sctx.Errorf("refresh token not found: %v", err)
+			auditRefreshFailed(sctx, r, userId, "refresh token not found")
 			http.Error(w, "refresh token not found", http.StatusUnauthorized)
 			return
 		}
-
-		if err := bcrypt.CompareHashAndPassword([]byte(ref.HashedToken), []byte(refreshPlain)); err != nil {
+		if err != nil {
 			sctx.Errorf("bcrypt compare fail: %v", err)
+			auditRefreshFailed(sctx, r, userId, "invalid refresh token")
 			http.Error(w, "invalid refresh token", http.StatusUnauthorized)
 			return
 		}
 
-		if ref.Used {
-			sctx.Error("Refresh token already used")
-			http.Error(w, "refresh token already used", http.StatusUnauthorized)
-			return
-		}
-
-		// если IP другой — отправляем mock email (лог в консоль)
-		clientIP := helpers.GetClientIP(r)
-		if clientIP != ref.IPAddress {
-			sctx.Warnf("WARNING: IP changed for user %s. Old IP=%s, new IP=%s", userId, ref.IPAddress, clientIP)
-		}
-
-		ref.Used = true
-		if err := sctx.GetDB().Save(&ref).Error; err != nil {
-			sctx.Errorf("DB error on update refresh token: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
-		}
-
-		newAccess, err := helpers.GenerateJWT(sctx, userId, clientIP)
-		if err != nil {
-			sctx.Errorf("generateJWT error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
-			return
-		}
-
-		newRefreshPlain, err := helpers.GenerateRandomBase64(32)
-		if err != nil {
-			sctx.Errorf("generateRandomBase64 error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
+		pair, err := RotateRefreshToken(sctx, r, ref, "")
+		switch {
+		case errors.Is(err, ErrRefreshTokenRevoked), errors.Is(err, ErrRefreshTokenReused),
+			errors.Is(err, sessions.ErrRefreshTokenExpired), errors.Is(err, sessions.ErrSessionIdle), errors.Is(err, sessions.ErrSessionTooOld):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
-		}
-
-		hashed, err := bcrypt.GenerateFromPassword([]byte(newRefreshPlain), bcrypt.DefaultCost)
-		if err != nil {
-			sctx.Errorf("bcrypt error: %v", err)
-			http.Error(w, "internal error", http.StatusInternalServerError)
+		case errors.Is(err, helpers.ErrIPBindingViolation):
+			http.Error(w, "refresh token is bound to another IP", http.StatusUnauthorized)
 			return
-		}
-
-		newRef := model.RefreshToken{
-			UserID:      userId,
-			HashedToken: string(hashed),
-			IPAddress:   clientIP,
-			Used:        false,
-		}
-		if err := sctx.GetDB().Save(&newRef).Error; err != nil {
-			sctx.Errorf("DB error: %v", err)
+		case err != nil:
+			sctx.Errorf("rotate refresh token error: %v", err)
 			http.Error(w, "internal error", http.StatusInternalServerError)
 			return
 		}
 
-		resp := map[string]string{
-			"access_token":  newAccess,
-			"refresh_token": newRefreshPlain,
-		}
-		writeJSON(w, resp)
+		helpers.WriteJSON(w, pair)
 	})
-}
 
-func writeJSON(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(data)
+	loginRoutes(r, sctx)
+	revokeRoutes(r, sctx)
+	apiKeyRoutes(r, sctx)
+	mfaRoutes(r, sctx)
+	passkeyRoutes(r, sctx)
+	passwordResetRoutes(r, sctx)
+	emailVerificationRoutes(r, sctx)
+	magicLinkRoutes(r, sctx)
+	sessionRoutes(r, sctx)
 }
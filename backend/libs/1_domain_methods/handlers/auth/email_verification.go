@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"test-task3/libs/1_domain_methods/email_verification"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type resendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+type changeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func emailVerificationRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	cfg := email_verification.LoadConfig(sctx)
+	lockoutCfg := lockout.LoadConfig(sctx)
+
+	verifyLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route: "auth_email_verify",
+		PerIP: "10/m",
+	})
+
+	resendLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_email_resend",
+		PerIP:   "5/m",
+		PerUser: "3/h",
+		UserID:  emailFromBody,
+	})
+
+	changeLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_email_change",
+		PerIP:   "5/m",
+		PerUser: "3/h",
+		// лимит стоит после Authenticate, claims уже в контексте запроса
+		UserID: func(r *http.Request) string {
+			return middlewares.RequestSmartContext(sctx, r).GetAccessClaims().GetUserID()
+		},
+	})
+
+	r.With(verifyLimit).Post("/auth/email/verify", func(w http.ResponseWriter, r *http.Request) {
+		var req verifyEmailRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Token == "" {
+			http.Error(w, "missing token", http.StatusBadRequest)
+			return
+		}
+
+		result, err := email_verification.Verify(sctx, req.Token)
+		if errors.Is(err, email_verification.ErrTokenInvalid) {
+			http.Error(w, "invalid or expired token", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, email_verification.ErrEmailTaken) {
+			http.Error(w, "email is already in use", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			sctx.Errorf("verify email error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		if result.PreviousEmail != "" {
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    result.UserID,
+				EventType: types.AuditEmailChanged,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"old_email": result.PreviousEmail, "new_email": result.Email},
+			})
+		}
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    result.UserID,
+			EventType: types.AuditEmailVerified,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"email": result.Email},
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	r.With(resendLimit).Post("/auth/email/verify/resend", func(w http.ResponseWriter, r *http.Request) {
+		var req resendVerificationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		email := normalizeEmail(req.Email)
+		if email == "" {
+			http.Error(w, "missing email", http.StatusBadRequest)
+			return
+		}
+
+		sendInBackground(sctx, r, func(sctx smart_context.ISmartContext, r *http.Request) {
+			resendVerification(sctx, cfg, r, email)
+		})
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	r.With(middlewares.Authenticate(sctx), requireUserToken, changeLimit).Post("/auth/email/change", func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := middlewares.RequestSmartContext(sctx, r)
+		userId := reqCtx.GetAccessClaims().GetUserID()
+
+		var req changeEmailRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		email := normalizeEmail(req.Email)
+		if email == "" || req.Password == "" {
+			http.Error(w, "missing email or password", http.StatusBadRequest)
+			return
+		}
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			http.Error(w, "invalid email", http.StatusBadRequest)
+			return
+		}
+
+		user, err := confirmPassword(reqCtx, lockoutCfg, r, userId, req.Password)
+		if err != nil {
+			writeLoginError(reqCtx, w, err)
+			return
+		}
+
+		err = email_verification.RequestChange(reqCtx, cfg, user, email)
+		if errors.Is(err, email_verification.ErrAlreadyVerified) {
+			http.Error(w, "email is already set", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, email_verification.ErrEmailTaken) {
+			http.Error(w, "email is already in use", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			reqCtx.Errorf("request email change error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		helpers.Audit(reqCtx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditEmailChangeRequested,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"old_email": user.Email, "new_email": email},
+		})
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// confirmPassword повторно проверяет пароль вошедшего пользователя перед чувствительным действием.
+// Неверный пароль засчитывается в блокировку, как при входе
+func confirmPassword(sctx smart_context.ISmartContext, cfg lockout.Config, r *http.Request, userId, password string) (*model.User, error) {
+	if err := lockout.Check(sctx, userId, helpers.GetClientIP(r)); err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	if err := sctx.GetDB().Where("id = ?", userId).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	if !verifyPassword(sctx, &user, password) {
+		registerLoginFailure(sctx, cfg, r, userId)
+		return nil, ErrInvalidCredentials
+	}
+	return &user, nil
+}
+
+func resendVerification(sctx smart_context.ISmartContext, cfg email_verification.Config, r *http.Request, email string) {
+	userId, err := email_verification.Resend(sctx, cfg, email)
+	if err != nil && !errors.Is(err, email_verification.ErrAlreadyVerified) {
+		sctx.Errorf("resend email verification error: %v", err)
+	}
+
+	outcome := types.AuditSuccess
+	if err != nil || userId == "" {
+		outcome = types.AuditFailure
+	}
+	details := types.Fields{"email": email}
+	if err != nil {
+		details["reason"] = err.Error()
+	}
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditEmailVerificationSent,
+		Outcome:   outcome,
+		Details:   details,
+	})
+}
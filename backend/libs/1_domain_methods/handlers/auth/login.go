@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"test-task3/libs/1_domain_methods/email_verification"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/mfa"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/1_domain_methods/passwords"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+const maxLoginBodySize = 1 << 20
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func loginRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	lockoutCfg := lockout.LoadConfig(sctx)
+	mfaCfg := mfa.LoadConfig(sctx)
+	verificationCfg := email_verification.LoadConfig(sctx)
+
+	loginLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_login",
+		PerIP:   "20/m",
+		PerUser: "10/m",
+		UserID:  emailFromBody,
+	})
+
+	r.With(loginLimit).Post("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		var req loginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Email == "" || req.Password == "" {
+			http.Error(w, "missing email or password", http.StatusBadRequest)
+			return
+		}
+
+		user, err := AuthenticatePassword(sctx, lockoutCfg, verificationCfg, r, req.Email, req.Password)
+		if err != nil {
+			writeLoginError(sctx, w, err)
+			return
+		}
+
+		// с включённым TOTP пароль — только первый шаг, токены выдаются после /auth/login/mfa
+		mfaToken, required, err := StartMFA(sctx, mfaCfg, r, user.ID, []string{types.AMRPassword})
+		if err != nil {
+			sctx.Errorf("start mfa error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if required {
+			helpers.WriteJSON(w, MFAChallenge{
+				MFARequired: true,
+				MFAToken:    mfaToken,
+				ExpiresIn:   int64(mfaCfg.ChallengeTTL.Seconds()),
+			})
+			return
+		}
+
+		pair, err := IssueTokenPair(sctx, TokenGrant{
+			UserID: user.ID,
+			AMR:    []string{types.AMRPassword},
+		}, r)
+		if err != nil {
+			writeIssueError(sctx, w, err)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    user.ID,
+			EventType: types.AuditTokenIssued,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"method": "password"},
+		})
+
+		helpers.WriteJSON(w, pair)
+	})
+}
+
+// AuthenticatePassword проверяет email и пароль с учётом блокировок аккаунта и IP и политики подтверждения email.
+// Ошибка — ErrInvalidCredentials, *lockout.LockedError, email_verification.ErrEmailNotVerified или ошибка БД
+func AuthenticatePassword(sctx smart_context.ISmartContext, cfg lockout.Config, verificationCfg email_verification.Config, r *http.Request, email, password string) (*model.User, error) {
+	clientIP := helpers.GetClientIP(r)
+
+	var user model.User
+	err := sctx.GetDB().Where("lower(email) = ?", normalizeEmail(email)).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	found := err == nil
+
+	if err := lockout.Check(sctx, user.ID, clientIP); err != nil {
+		if _, ok := lockout.IsLocked(err); ok {
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    user.ID,
+				EventType: types.AuditLoginFailed,
+				Outcome:   types.AuditFailure,
+				Details:   types.Fields{"email": email, "reason": "locked"},
+			})
+		}
+		return nil, err
+	}
+
+	valid := false
+	if found {
+		valid = verifyPassword(sctx, &user, password)
+	} else {
+		// время ответа не должно выдавать, есть ли такой email
+		passwords.VerifyDummy(password)
+	}
+
+	if !valid {
+		sctx.Warnf("Failed login for email %s from IP=%s", email, clientIP)
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    user.ID,
+			EventType: types.AuditLoginFailed,
+			Outcome:   types.AuditFailure,
+			Details:   types.Fields{"email": email, "reason": "invalid credentials"},
+		})
+
+		registerLoginFailure(sctx, cfg, r, user.ID)
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := lockout.RegisterSuccess(sctx, user.ID); err != nil {
+		sctx.Errorf("register login success error: %v", err)
+	}
+
+	// пароль верный, поэтому ответ о неподтверждённом email не помогает перебирать адреса
+	if err := email_verification.CheckLogin(verificationCfg, &user); err != nil {
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    user.ID,
+			EventType: types.AuditLoginFailed,
+			Outcome:   types.AuditFailure,
+			Details:   types.Fields{"email": email, "reason": "email not verified"},
+		})
+		return nil, err
+	}
+
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    user.ID,
+		EventType: types.AuditLoginSucceeded,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"method": "password"},
+	})
+
+	return &user, nil
+}
+
+// verifyPassword сравнивает пароль с хэшем пользователя. Хэш старым алгоритмом или с прежними
+// параметрами после успешной проверки прозрачно пересчитывается текущими
+func verifyPassword(sctx smart_context.ISmartContext, user *model.User, password string) bool {
+	ok, needsRehash, err := passwords.Verify(password, user.Password)
+	if err != nil {
+		sctx.Errorf("verify password hash of user %s error: %v", user.ID, err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	if needsRehash {
+		if err := rehashPassword(sctx, user, password); err != nil {
+			sctx.Errorf("rehash password of user %s error: %v", user.ID, err)
+		}
+	}
+	return true
+}
+
+func rehashPassword(sctx smart_context.ISmartContext, user *model.User, password string) error {
+	hash, err := passwords.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	// условие на старый хэш — чтобы не затереть пароль, сменённый параллельно
+	err = sctx.GetDB().Model(&model.User{}).
+		Where("id = ? AND password = ?", user.ID, user.Password).
+		Update("password", hash).Error
+	if err != nil {
+		return err
+	}
+
+	sctx.Infof("Password hash of user %s upgraded", user.ID)
+	user.Password = hash
+	return nil
+}
+
+// registerLoginFailure засчитывает неудачную попытку входа аккаунту и IP и пишет в аудит новые блокировки
+func registerLoginFailure(sctx smart_context.ISmartContext, cfg lockout.Config, r *http.Request, userId string) {
+	locks, err := lockout.RegisterFailure(sctx, cfg, userId, helpers.GetClientIP(r))
+	if err != nil {
+		sctx.Errorf("register login failure error: %v", err)
+	}
+	for _, lock := range locks {
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditAccountLocked,
+			Outcome:   types.AuditSuccess,
+			Details: types.Fields{
+				"subject_type": lock.SubjectType,
+				"subject":      lock.Subject,
+				"locked_until": lock.Until,
+			},
+		})
+	}
+}
+
+func writeLoginError(sctx smart_context.ISmartContext, w http.ResponseWriter, err error) {
+	if lockedErr, ok := lockout.IsLocked(err); ok {
+		retryAfter := int(math.Ceil(time.Until(lockedErr.Until).Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		http.Error(w, "too many failed login attempts, try again later", http.StatusTooManyRequests)
+		return
+	}
+	if errors.Is(err, ErrInvalidCredentials) {
+		http.Error(w, "invalid email or password", http.StatusUnauthorized)
+		return
+	}
+	if errors.Is(err, email_verification.ErrEmailNotVerified) {
+		http.Error(w, "email is not verified", http.StatusForbidden)
+		return
+	}
+
+	sctx.Errorf("login error: %v", err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+// emailFromBody достаёт email из JSON-тела для лимита по пользователю, тело при этом остаётся доступным хэндлеру
+func emailFromBody(r *http.Request) string {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxLoginBodySize))
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var req loginRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return normalizeEmail(req.Email)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
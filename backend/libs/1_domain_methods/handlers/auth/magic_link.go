@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"mime"
+	"net/http"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/magic_link"
+	"test-task3/libs/1_domain_methods/mfa"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// magicLinkDeviceCookie — токен устройства для страницы подтверждения, открытой в том же браузере
+const magicLinkDeviceCookie = "magic_link_device"
+
+type magicLinkRequest struct {
+	Email string `json:"email"`
+	// BindDevice — ссылка сработает только вместе с device_token из ответа
+	BindDevice bool `json:"bind_device"`
+}
+
+type magicLinkResponse struct {
+	DeviceToken string `json:"device_token,omitempty"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type magicLinkVerifyRequest struct {
+	Token       string `json:"token"`
+	DeviceToken string `json:"device_token"`
+}
+
+// magicLinkTemplate — страница по ссылке из письма. GET ничего не гасит: сканеры почты, которые
+// открывают ссылки заранее, не сожгут её и не войдут за пользователя, вход — только по кнопке (POST)
+var magicLinkTemplate = template.Must(template.New("magic-link").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Sign in</title>
+</head>
+<body>
+<h1>Sign in</h1>
+<form method="post" action="/auth/magic-link/verify">
+<input type="hidden" name="token" value="{{.}}">
+<p><button type="submit">Continue signing in</button></p>
+</form>
+</body>
+</html>
+`))
+
+func magicLinkRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	cfg := magic_link.LoadConfig(sctx)
+	mfaCfg := mfa.LoadConfig(sctx)
+
+	sendLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_magic_link",
+		PerIP:   "5/m",
+		PerUser: "5/h",
+		UserID:  emailFromBody,
+	})
+
+	verifyLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route: "auth_magic_link_verify",
+		PerIP: "10/m",
+	})
+
+	r.With(sendLimit).Post("/auth/magic-link", func(w http.ResponseWriter, r *http.Request) {
+		var req magicLinkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		email := normalizeEmail(req.Email)
+		if email == "" {
+			http.Error(w, "missing email", http.StatusBadRequest)
+			return
+		}
+
+		// токен устройства выдаётся и для неизвестного email, чтобы ответы не различались
+		resp := magicLinkResponse{ExpiresIn: int64(cfg.TTL.Seconds())}
+		if req.BindDevice || cfg.RequireDevice {
+			deviceToken, err := helpers.GenerateRandomHex(32)
+			if err != nil {
+				sctx.Errorf("generate device token error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			resp.DeviceToken = deviceToken
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     magicLinkDeviceCookie,
+				Value:    deviceToken,
+				Path:     "/auth/magic-link",
+				MaxAge:   int(cfg.TTL.Seconds()),
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		sendInBackground(sctx, r, func(sctx smart_context.ISmartContext, r *http.Request) {
+			sendMagicLink(sctx, cfg, r, email, resp.DeviceToken)
+		})
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	r.With(verifyLimit).Get("/auth/magic-link/verify", func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			http.Error(w, "missing token", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		// токен в адресе страницы не должен уйти дальше через Referer
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Content-Security-Policy", "default-src 'none'; form-action 'self'; frame-ancestors 'none'")
+
+		if err := magicLinkTemplate.Execute(w, token); err != nil {
+			sctx.Errorf("render magic link page error: %v", err)
+		}
+	})
+
+	r.With(verifyLimit).Post("/auth/magic-link/verify", func(w http.ResponseWriter, r *http.Request) {
+		req, ok := decodeMagicLinkVerify(w, r)
+		if !ok {
+			return
+		}
+		if req.DeviceToken == "" {
+			if cookie, err := r.Cookie(magicLinkDeviceCookie); err == nil {
+				req.DeviceToken = cookie.Value
+			}
+		}
+
+		// ссылку подобрать нельзя (она подписана), но заблокированный аккаунт или IP не входит и по ней.
+		// Проверка до гашения ссылки, иначе она сгорела бы впустую
+		userId, err := magic_link.Consume(sctx, cfg, req.Token, req.DeviceToken, func(userId string) error {
+			return lockout.Check(sctx, userId, helpers.GetClientIP(r))
+		})
+		if errors.Is(err, magic_link.ErrLinkInvalid) || errors.Is(err, magic_link.ErrDeviceMismatch) {
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    userId,
+				EventType: types.AuditLoginFailed,
+				Outcome:   types.AuditFailure,
+				Details:   types.Fields{"method": "magic_link", "reason": err.Error()},
+			})
+			if errors.Is(err, magic_link.ErrDeviceMismatch) {
+				http.Error(w, "open the link on the device that requested it", http.StatusForbidden)
+				return
+			}
+			http.Error(w, "invalid or expired link", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			writeLoginError(sctx, w, err)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditLoginSucceeded,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"method": "magic_link"},
+		})
+
+		http.SetCookie(w, &http.Cookie{Name: magicLinkDeviceCookie, Path: "/auth/magic-link", MaxAge: -1})
+
+		amr := []string{types.AMREmailLink}
+		mfaToken, required, err := StartMFA(sctx, mfaCfg, r, userId, amr)
+		if err != nil {
+			sctx.Errorf("start mfa error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if required {
+			helpers.WriteJSON(w, MFAChallenge{
+				MFARequired: true,
+				MFAToken:    mfaToken,
+				ExpiresIn:   int64(mfaCfg.ChallengeTTL.Seconds()),
+			})
+			return
+		}
+
+		pair, err := IssueTokenPair(sctx, TokenGrant{UserID: userId, AMR: amr}, r)
+		if err != nil {
+			writeIssueError(sctx, w, err)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditTokenIssued,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"method": "magic_link"},
+		})
+
+		helpers.WriteJSON(w, pair)
+	})
+}
+
+// decodeMagicLinkVerify принимает JSON от приложения и форму со страницы подтверждения
+func decodeMagicLinkVerify(w http.ResponseWriter, r *http.Request) (*magicLinkVerifyRequest, bool) {
+	var req magicLinkVerifyRequest
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return nil, false
+		}
+		req.Token = r.PostForm.Get("token")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if req.Token == "" {
+		http.Error(w, "missing token", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+func sendMagicLink(sctx smart_context.ISmartContext, cfg magic_link.Config, r *http.Request, email, deviceToken string) {
+	userId, err := magic_link.Send(sctx, cfg, email, helpers.GetClientIP(r), deviceToken)
+	if err != nil {
+		sctx.Errorf("send magic link error: %v", err)
+	}
+
+	outcome := types.AuditSuccess
+	if err != nil || userId == "" {
+		outcome = types.AuditFailure
+	}
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditMagicLinkSent,
+		Outcome:   outcome,
+		Details:   types.Fields{"email": email, "device_bound": deviceToken != ""},
+	})
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+// backgroundMailTimeout ограничивает отправку письма, которая идёт уже после ответа клиенту
+const backgroundMailTimeout = 30 * time.Second
+
+// sendInBackground выполняет send после ответа: поиск пользователя по email и письмо не влияют ни на статус,
+// ни на время ответа, поэтому по ним нельзя узнать, зарегистрирован ли адрес. r сохраняет IP и request id для аудита
+func sendInBackground(sctx smart_context.ISmartContext, r *http.Request, send func(sctx smart_context.ISmartContext, r *http.Request)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), backgroundMailTimeout)
+	go func() {
+		defer cancel()
+		send(sctx.WithContext(ctx), r.WithContext(ctx))
+	}()
+}
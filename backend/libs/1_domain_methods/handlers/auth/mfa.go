@@ -0,0 +1,308 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/mfa"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MFAChallenge — ответ /auth/login, когда после пароля нужен код второго фактора
+type MFAChallenge struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type mfaLoginRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func mfaRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	lockoutCfg := lockout.LoadConfig(sctx)
+	mfaCfg := mfa.LoadConfig(sctx)
+
+	mfaLoginLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route: "auth_login_mfa",
+		PerIP: "20/m",
+	})
+
+	// отключение TOTP и новые recovery-коды подтверждаются кодом второго фактора: перебор кода по украденному
+	// access-токену ограничен так же, как второй шаг входа
+	mfaCodeLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_mfa_code",
+		PerIP:   "20/m",
+		PerUser: "5/m",
+		UserID:  userIdFromClaims,
+	})
+
+	r.With(mfaLoginLimit).Post("/auth/login/mfa", func(w http.ResponseWriter, r *http.Request) {
+		var req mfaLoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.MFAToken == "" || req.Code == "" {
+			http.Error(w, "missing mfa_token or code", http.StatusBadRequest)
+			return
+		}
+
+		userId, amr, err := CompleteMFA(sctx, mfaCfg, lockoutCfg, r, req.MFAToken, req.Code)
+		if errors.Is(err, mfa.ErrChallengeInvalid) {
+			http.Error(w, "invalid or expired mfa_token, log in again", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			http.Error(w, "invalid code", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			writeLoginError(sctx, w, err)
+			return
+		}
+
+		pair, err := IssueTokenPair(sctx, TokenGrant{UserID: userId, AMR: amr}, r)
+		if err != nil {
+			writeIssueError(sctx, w, err)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditTokenIssued,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"method": "mfa", "amr": amr},
+		})
+
+		helpers.WriteJSON(w, pair)
+	})
+
+	r.Route("/auth/mfa", func(r chi.Router) {
+		r.Use(middlewares.Authenticate(sctx))
+		r.Use(requireUserToken)
+
+		r.Post("/totp/enroll", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+
+			enrollment, err := mfa.Enroll(reqCtx, mfaCfg, reqCtx.GetAccessClaims().GetUserID())
+			if errors.Is(err, mfa.ErrAlreadyEnrolled) {
+				http.Error(w, "totp is already enabled", http.StatusConflict)
+				return
+			}
+			if err != nil {
+				reqCtx.Errorf("enroll totp error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, enrollment)
+		})
+
+		r.Post("/totp/confirm", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			userId := reqCtx.GetAccessClaims().GetUserID()
+
+			code, ok := decodeMFACode(w, r)
+			if !ok {
+				return
+			}
+
+			codes, err := mfa.Confirm(reqCtx, userId, code)
+			if err != nil {
+				writeMFAError(reqCtx, w, "confirm totp", err)
+				return
+			}
+
+			helpers.Audit(reqCtx, r, types.AuditEvent{
+				UserID:    userId,
+				EventType: types.AuditMFAEnabled,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"method": mfa.MethodTOTP},
+			})
+
+			helpers.WriteJSON(w, recoveryCodesResponse{RecoveryCodes: codes})
+		})
+
+		r.With(mfaCodeLimit).Delete("/totp", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			userId := reqCtx.GetAccessClaims().GetUserID()
+
+			code, ok := decodeMFACode(w, r)
+			if !ok {
+				return
+			}
+			if err := lockout.Check(reqCtx, userId, helpers.GetClientIP(r)); err != nil {
+				writeLoginError(reqCtx, w, err)
+				return
+			}
+
+			method, err := mfa.Disable(reqCtx, userId, code)
+			if err != nil {
+				registerMFAFailure(reqCtx, lockoutCfg, r, userId, "disable_totp", err)
+				writeMFAError(reqCtx, w, "disable totp", err)
+				return
+			}
+
+			helpers.Audit(reqCtx, r, types.AuditEvent{
+				UserID:    userId,
+				EventType: types.AuditMFADisabled,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"verified_with": method},
+			})
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.With(mfaCodeLimit).Post("/recovery-codes", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			userId := reqCtx.GetAccessClaims().GetUserID()
+
+			code, ok := decodeMFACode(w, r)
+			if !ok {
+				return
+			}
+			if err := lockout.Check(reqCtx, userId, helpers.GetClientIP(r)); err != nil {
+				writeLoginError(reqCtx, w, err)
+				return
+			}
+
+			codes, err := mfa.RegenerateRecoveryCodes(reqCtx, userId, code)
+			if err != nil {
+				registerMFAFailure(reqCtx, lockoutCfg, r, userId, "regenerate_recovery_codes", err)
+				writeMFAError(reqCtx, w, "regenerate recovery codes", err)
+				return
+			}
+
+			helpers.Audit(reqCtx, r, types.AuditEvent{
+				UserID:    userId,
+				EventType: types.AuditRecoveryCodesRegenerated,
+				Outcome:   types.AuditSuccess,
+			})
+
+			helpers.WriteJSON(w, recoveryCodesResponse{RecoveryCodes: codes})
+		})
+	})
+}
+
+// StartMFA после первого фактора (amr) решает, нужен ли второй шаг. Если у пользователя включён TOTP,
+// возвращает required=true и токен для CompleteMFA
+func StartMFA(sctx smart_context.ISmartContext, cfg mfa.Config, r *http.Request, userId string, amr []string) (string, bool, error) {
+	enabled, err := mfa.IsEnabled(sctx, userId)
+	if err != nil || !enabled {
+		return "", false, err
+	}
+
+	token, err := mfa.CreateChallenge(sctx, cfg, userId, helpers.GetClientIP(r), amr)
+	if err != nil {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// CompleteMFA проверяет код второго шага входа с учётом блокировок и возвращает id пользователя
+// и amr обоих шагов. Ошибка — mfa.ErrChallengeInvalid, mfa.ErrInvalidCode, *lockout.LockedError или ошибка БД
+func CompleteMFA(sctx smart_context.ISmartContext, cfg mfa.Config, lockoutCfg lockout.Config, r *http.Request, token, code string) (string, []string, error) {
+	// заблокированный IP не перебирает коды даже по чужим токенам
+	if err := lockout.Check(sctx, "", helpers.GetClientIP(r)); err != nil {
+		return "", nil, err
+	}
+
+	userId, method, amr, err := mfa.CompleteChallenge(sctx, cfg, token, code)
+	if errors.Is(err, mfa.ErrInvalidCode) || errors.Is(err, mfa.ErrChallengeInvalid) {
+		if userId == "" {
+			return "", nil, err
+		}
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditMFAFailed,
+			Outcome:   types.AuditFailure,
+			Details:   types.Fields{"reason": err.Error()},
+		})
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			registerLoginFailure(sctx, lockoutCfg, r, userId)
+		}
+		return "", nil, err
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := lockout.RegisterSuccess(sctx, userId); err != nil {
+		sctx.Errorf("register login success error: %v", err)
+	}
+
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditMFASucceeded,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"method": method},
+	})
+
+	return userId, amr, nil
+}
+
+// registerMFAFailure записывает неверный код второго фактора в аудит и в счётчик блокировок, как неудачный вход
+func registerMFAFailure(sctx smart_context.ISmartContext, cfg lockout.Config, r *http.Request, userId, action string, err error) {
+	if !errors.Is(err, mfa.ErrInvalidCode) {
+		return
+	}
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditMFAFailed,
+		Outcome:   types.AuditFailure,
+		Details:   types.Fields{"reason": err.Error(), "action": action},
+	})
+	registerLoginFailure(sctx, cfg, r, userId)
+}
+
+// userIdFromClaims — id пользователя из access-токена запроса, для лимитов за Authenticate
+func userIdFromClaims(r *http.Request) string {
+	reqCtx, ok := smart_context.FromContext(r.Context())
+	if !ok || reqCtx.GetAccessClaims() == nil {
+		return ""
+	}
+	return reqCtx.GetAccessClaims().GetUserID()
+}
+
+func decodeMFACode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req mfaCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return "", false
+	}
+	if req.Code == "" {
+		http.Error(w, "missing code", http.StatusBadRequest)
+		return "", false
+	}
+	return req.Code, true
+}
+
+func writeMFAError(sctx smart_context.ISmartContext, w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, mfa.ErrInvalidCode):
+		http.Error(w, "invalid code", http.StatusBadRequest)
+	case errors.Is(err, mfa.ErrNotEnrolled):
+		http.Error(w, "totp is not enrolled", http.StatusConflict)
+	case errors.Is(err, mfa.ErrAlreadyEnrolled):
+		http.Error(w, "totp is already enabled", http.StatusConflict)
+	default:
+		sctx.Errorf("%s error: %v", action, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
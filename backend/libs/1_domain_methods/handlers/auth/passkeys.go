@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"test-task3/libs/1_domain_methods/email_verification"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/1_domain_methods/passkeys"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// passkeyFinishRequest — ответ navigator.credentials.create()/get() как есть и id challenge из begin
+type passkeyFinishRequest struct {
+	ChallengeID string          `json:"challenge_id"`
+	Name        string          `json:"name"`
+	Credential  json.RawMessage `json:"credential"`
+}
+
+func passkeyRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	rp, err := passkeys.NewRelyingParty(passkeys.LoadConfig(sctx))
+	if err != nil {
+		sctx.Fatalf("Error configuring WebAuthn: %v", err)
+	}
+	verificationCfg := email_verification.LoadConfig(sctx)
+
+	passkeyLoginLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route: "auth_webauthn_login",
+		PerIP: "20/m",
+	})
+
+	r.Route("/auth/webauthn", func(r chi.Router) {
+		r.With(passkeyLoginLimit).Post("/login/begin", func(w http.ResponseWriter, r *http.Request) {
+			ceremony, err := rp.BeginLogin(sctx)
+			if err != nil {
+				sctx.Errorf("begin passkey login error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, ceremony)
+		})
+
+		r.With(passkeyLoginLimit).Post("/login/finish", func(w http.ResponseWriter, r *http.Request) {
+			req, ok := decodePasskeyFinish(w, r)
+			if !ok {
+				return
+			}
+
+			result, err := rp.FinishLogin(sctx, req.ChallengeID, req.Credential)
+			if err != nil {
+				if isPasskeyRejected(err) {
+					sctx.Warnf("Passkey login failed from IP=%s: %v", helpers.GetClientIP(r), err)
+					helpers.Audit(sctx, r, types.AuditEvent{
+						EventType: types.AuditLoginFailed,
+						Outcome:   types.AuditFailure,
+						Details:   types.Fields{"method": "passkey", "reason": err.Error()},
+					})
+				}
+				writePasskeyError(sctx, w, "passkey login", http.StatusUnauthorized, err)
+				return
+			}
+
+			// подпись passkey не подобрать, но заблокированный аккаунт или IP не входит и с ней
+			if err := lockout.Check(sctx, result.UserID, helpers.GetClientIP(r)); err != nil {
+				if _, ok := lockout.IsLocked(err); ok {
+					helpers.Audit(sctx, r, types.AuditEvent{
+						UserID:    result.UserID,
+						EventType: types.AuditLoginFailed,
+						Outcome:   types.AuditFailure,
+						Details:   types.Fields{"method": "passkey", "reason": "locked"},
+					})
+				}
+				writeLoginError(sctx, w, err)
+				return
+			}
+
+			err = email_verification.CheckLoginByID(sctx, verificationCfg, result.UserID)
+			if errors.Is(err, email_verification.ErrEmailNotVerified) {
+				helpers.Audit(sctx, r, types.AuditEvent{
+					UserID:    result.UserID,
+					EventType: types.AuditLoginFailed,
+					Outcome:   types.AuditFailure,
+					Details:   types.Fields{"method": "passkey", "reason": "email not verified"},
+				})
+				http.Error(w, "email is not verified", http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				sctx.Errorf("check email verification error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    result.UserID,
+				EventType: types.AuditLoginSucceeded,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"method": "passkey"},
+			})
+
+			pair, err := IssueTokenPair(sctx, TokenGrant{UserID: result.UserID, AMR: result.AMR}, r)
+			if err != nil {
+				writeIssueError(sctx, w, err)
+				return
+			}
+
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    result.UserID,
+				EventType: types.AuditTokenIssued,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"method": "passkey"},
+			})
+
+			helpers.WriteJSON(w, pair)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.Authenticate(sctx))
+			r.Use(requireUserToken)
+
+			r.Post("/register/begin", func(w http.ResponseWriter, r *http.Request) {
+				reqCtx := middlewares.RequestSmartContext(sctx, r)
+
+				ceremony, err := rp.BeginRegistration(reqCtx, reqCtx.GetAccessClaims().GetUserID())
+				if err != nil {
+					reqCtx.Errorf("begin passkey registration error: %v", err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+
+				helpers.WriteJSON(w, ceremony)
+			})
+
+			r.Post("/register/finish", func(w http.ResponseWriter, r *http.Request) {
+				reqCtx := middlewares.RequestSmartContext(sctx, r)
+				userId := reqCtx.GetAccessClaims().GetUserID()
+
+				req, ok := decodePasskeyFinish(w, r)
+				if !ok {
+					return
+				}
+
+				credential, err := rp.FinishRegistration(reqCtx, userId, req.ChallengeID, req.Name, req.Credential)
+				if err != nil {
+					writePasskeyError(reqCtx, w, "passkey registration", http.StatusBadRequest, err)
+					return
+				}
+
+				helpers.Audit(reqCtx, r, types.AuditEvent{
+					UserID:    userId,
+					EventType: types.AuditPasskeyRegistered,
+					Outcome:   types.AuditSuccess,
+					Details:   types.Fields{"passkey_id": credential.ID},
+				})
+
+				helpers.WriteJSON(w, credential)
+			})
+
+			r.Get("/credentials", func(w http.ResponseWriter, r *http.Request) {
+				reqCtx := middlewares.RequestSmartContext(sctx, r)
+
+				credentials, err := passkeys.List(reqCtx, reqCtx.GetAccessClaims().GetUserID())
+				if err != nil {
+					reqCtx.Errorf("list passkeys error: %v", err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+
+				helpers.WriteJSON(w, credentials)
+			})
+
+			r.Delete("/credentials/{passkeyId}", func(w http.ResponseWriter, r *http.Request) {
+				reqCtx := middlewares.RequestSmartContext(sctx, r)
+				userId := reqCtx.GetAccessClaims().GetUserID()
+				passkeyId := chi.URLParam(r, "passkeyId")
+
+				err := passkeys.Delete(reqCtx, userId, passkeyId)
+				if errors.Is(err, passkeys.ErrCredentialNotFound) {
+					http.Error(w, "passkey not found", http.StatusNotFound)
+					return
+				}
+				if err != nil {
+					reqCtx.Errorf("delete passkey error: %v", err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+
+				helpers.Audit(reqCtx, r, types.AuditEvent{
+					UserID:    userId,
+					EventType: types.AuditPasskeyDeleted,
+					Outcome:   types.AuditSuccess,
+					Details:   types.Fields{"passkey_id": passkeyId},
+				})
+
+				w.WriteHeader(http.StatusNoContent)
+			})
+		})
+	})
+}
+
+func decodePasskeyFinish(w http.ResponseWriter, r *http.Request) (*passkeyFinishRequest, bool) {
+	var req passkeyFinishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if req.ChallengeID == "" || len(req.Credential) == 0 {
+		http.Error(w, "missing challenge_id or credential", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+// isPasskeyRejected — ответ аутентификатора не прошёл проверку, это не сбой сервера
+func isPasskeyRejected(err error) bool {
+	return errors.Is(err, passkeys.ErrVerificationFailed) ||
+		errors.Is(err, passkeys.ErrClonedAuthenticator) ||
+		errors.Is(err, passkeys.ErrCredentialNotFound)
+}
+
+// writePasskeyError отвечает на ошибку церемонии; rejectedStatus — код для отклонённого ответа аутентификатора
+func writePasskeyError(sctx smart_context.ISmartContext, w http.ResponseWriter, action string, rejectedStatus int, err error) {
+	switch {
+	case errors.Is(err, passkeys.ErrChallengeNotFound):
+		http.Error(w, "webauthn challenge not found or expired", http.StatusBadRequest)
+	case errors.Is(err, passkeys.ErrCredentialExists):
+		http.Error(w, "passkey is already registered", http.StatusConflict)
+	case isPasskeyRejected(err):
+		http.Error(w, err.Error(), rejectedStatus)
+	default:
+		sctx.Errorf("%s error: %v", action, err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/1_domain_methods/password_policy"
+	"test-task3/libs/1_domain_methods/password_reset"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func passwordResetRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	cfg := password_reset.LoadConfig(sctx)
+	policy := password_policy.LoadConfig(sctx)
+
+	forgotLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "auth_password_forgot",
+		PerIP:   "5/m",
+		PerUser: "3/h",
+		UserID:  emailFromBody,
+	})
+
+	resetLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route: "auth_password_reset",
+		PerIP: "10/m",
+	})
+
+	r.With(forgotLimit).Post("/auth/password/forgot", func(w http.ResponseWriter, r *http.Request) {
+		var req forgotPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		email := normalizeEmail(req.Email)
+		if email == "" {
+			http.Error(w, "missing email", http.StatusBadRequest)
+			return
+		}
+
+		sendInBackground(sctx, r, func(sctx smart_context.ISmartContext, r *http.Request) {
+			requestPasswordReset(sctx, cfg, r, email)
+		})
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	r.With(resetLimit).Post("/auth/password/reset", func(w http.ResponseWriter, r *http.Request) {
+		var req resetPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Token == "" || req.Password == "" {
+			http.Error(w, "missing token or password", http.StatusBadRequest)
+			return
+		}
+
+		// старый пароль мог быть украден: все сессии завершаются вместе со сменой пароля
+		userId, err := password_reset.Reset(sctx, policy, req.Token, req.Password, func(txCtx smart_context.ISmartContext, userId string, before time.Time) error {
+			return RevokeAllUserTokens(txCtx, r, userId, before, "password reset")
+		})
+		if writePasswordPolicyError(w, err) {
+			return
+		}
+		if errors.Is(err, password_reset.ErrTokenInvalid) {
+			helpers.Audit(sctx, r, types.AuditEvent{
+				UserID:    userId,
+				EventType: types.AuditPasswordReset,
+				Outcome:   types.AuditFailure,
+				Details:   types.Fields{"reason": err.Error()},
+			})
+			http.Error(w, "invalid or expired token", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			sctx.Errorf("reset password error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditPasswordReset,
+			Outcome:   types.AuditSuccess,
+		})
+
+		// блокировка после перебора больше не нужна
+		if err := lockout.RegisterSuccess(sctx, userId); err != nil {
+			sctx.Errorf("reset lockout error: %v", err)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+type passwordPolicyErrorResponse struct {
+	Error string `json:"error"`
+	*password_policy.ValidationError
+}
+
+// writePasswordPolicyError отвечает 400 со списком нарушений, если пароль не прошёл политику
+func writePasswordPolicyError(w http.ResponseWriter, err error) bool {
+	var validationErr *password_policy.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(passwordPolicyErrorResponse{
+		Error:           "password does not meet the policy",
+		ValidationError: validationErr,
+	})
+	return true
+}
+
+func requestPasswordReset(sctx smart_context.ISmartContext, cfg password_reset.Config, r *http.Request, email string) {
+	userId, err := password_reset.RequestReset(sctx, cfg, email, helpers.GetClientIP(r))
+	if err != nil {
+		sctx.Errorf("request password reset error: %v", err)
+	}
+
+	outcome := types.AuditSuccess
+	if err != nil || userId == "" {
+		outcome = types.AuditFailure
+	}
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditPasswordResetRequested,
+		Outcome:   outcome,
+		Details:   types.Fields{"email": email},
+	})
+}
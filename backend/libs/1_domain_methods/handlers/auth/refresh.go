@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+var (
+	ErrRefreshTokenRevoked = errors.New("session revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token already used")
+)
+
+// RotateRefreshToken меняет найденный refresh-токен на новую пару той же сессии. Общий для /auth/refresh
+// и grant_type=refresh_token в /oauth/token. scope — права новой пары, пустой — как у старой.
+// Повторное использование отзывает все токены пользователя; отказы и успех пишутся в аудит
+func RotateRefreshToken(sctx smart_context.ISmartContext, r *http.Request, ref *model.RefreshToken, scope string) (*TokenPair, error) {
+	userId := ref.UserID
+
+	// отозванная сессия — не кража: устройство просто ещё не знает, что из него вышли
+	if !ref.RevokedAt.IsZero() {
+		auditRefreshFailed(sctx, r, userId, "session revoked")
+		return nil, ErrRefreshTokenRevoked
+	}
+
+	if ref.Used {
+		// повторное использование — признак кражи токена: отзываем все выданные пользователю токены
+		sctx.Error("Refresh token already used")
+		if err := RevokeAllUserTokens(sctx, r, userId, time.Now(), "refresh token reuse"); err != nil {
+			sctx.Errorf("revoke user tokens error: %v", err)
+		}
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditRefreshReuse,
+			Outcome:   types.AuditFailure,
+			Details:   types.Fields{"refresh_token_id": ref.ID, "client_id": ref.ClientID},
+		})
+		return nil, ErrRefreshTokenReused
+	}
+
+	if err := sessions.CheckExpiry(ref, time.Now()); err != nil {
+		auditRefreshFailed(sctx, r, userId, err.Error())
+		return nil, err
+	}
+
+	// если IP другой — отправляем mock email (лог в консоль), а политика решает, пускать ли дальше
+	clientIP := helpers.GetClientIP(r)
+	bindingErr := helpers.CheckIPBinding(sctx, helpers.IPBindingScopeRefreshToken, ref.IPAddress, clientIP)
+	if clientIP != ref.IPAddress {
+		sctx.Warnf("WARNING: IP changed for user %s. Old IP=%s, new IP=%s", userId, ref.IPAddress, clientIP)
+
+		outcome := types.AuditSuccess
+		if bindingErr != nil {
+			outcome = types.AuditFailure
+		}
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditIPChanged,
+			Outcome:   outcome,
+			Details: types.Fields{
+				"old_ip": ref.IPAddress,
+				"new_ip": clientIP,
+				"policy": helpers.GetIPBindingPolicy(),
+			},
+		})
+	}
+	if bindingErr != nil {
+		return nil, bindingErr
+	}
+
+	marked, err := markRefreshTokenUsed(sctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		auditRefreshFailed(sctx, r, userId, "refresh token used concurrently")
+		return nil, ErrRefreshTokenReused
+	}
+
+	if scope == "" {
+		scope = ref.Scope
+	}
+	pair, err := IssueTokenPair(sctx, TokenGrant{
+		UserID:     userId,
+		ClientID:   ref.ClientID,
+		Scope:      scope,
+		AMR:        strings.Fields(ref.Amr),
+		SessionID:  ref.FamilyID,
+		DeviceName: ref.DeviceName,
+
+		SessionStartedAt: ref.SessionStartedAt,
+	}, r)
+	if err != nil {
+		return nil, err
+	}
+
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditTokenRefreshed,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"refresh_token_id": ref.ID, "client_id": ref.ClientID},
+	})
+
+	return pair, nil
+}
+
+func auditRefreshFailed(sctx smart_context.ISmartContext, r *http.Request, userId, reason string) {
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditRefreshFailed,
+		Outcome:   types.AuditFailure,
+		Details:   types.Fields{"reason": reason},
+	})
+}
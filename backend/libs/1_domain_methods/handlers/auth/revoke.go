@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"test-task3/libs/1_domain_methods/api_keys"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type revokeRequest struct {
+	// All — отозвать все токены пользователя, а не только текущий access-токен
+	All bool `json:"all"`
+}
+
+func revokeRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	r.With(middlewares.Authenticate(sctx)).Post("/auth/revoke", func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := middlewares.RequestSmartContext(sctx, r)
+		claims := reqCtx.GetAccessClaims()
+
+		var req revokeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.All {
+			if err := RevokeAllUserTokens(reqCtx, r, claims.GetUserID(), time.Now(), "user request"); err != nil {
+				reqCtx.Errorf("revoke user tokens error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// запрос с API-ключом отзывает сам ключ
+		if claims.APIKeyID != "" {
+			if err := api_keys.Revoke(reqCtx, claims.GetUserID(), claims.APIKeyID); err != nil {
+				reqCtx.Errorf("revoke api key error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+			auditAPIKey(reqCtx, r, types.AuditAPIKeyRevoked, claims.GetUserID(), claims.APIKeyID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if err := reqCtx.GetTokenService().Revoke(r.Context(), claims, "user request"); err != nil {
+			reqCtx.Errorf("revoke token error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		helpers.Audit(reqCtx, r, types.AuditEvent{
+			UserID:    claims.GetUserID(),
+			EventType: types.AuditTokenRevoked,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"jti": claims.ID, "reason": "user request"},
+		})
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// RevokeAllUserTokens отзывает access-токены пользователя, выпущенные не позже before,
+// и завершает его сессии, начатые или обновлённые в том же периоде
+func RevokeAllUserTokens(sctx smart_context.ISmartContext, r *http.Request, userId string, before time.Time, reason string) error {
+	if err := sctx.GetTokenService().RevokeAllForUser(r.Context(), userId, before, reason); err != nil {
+		return err
+	}
+
+	err := sctx.GetDB().Model(&model.RefreshToken{}).
+		Where("user_id = ? AND used = false AND revoked_at IS NULL AND created_at <= ?", userId, before.UTC()).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return err
+	}
+
+	sctx.Warnf("All tokens of user %s issued before %s revoked: %s", userId, before.Format(time.RFC3339), reason)
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    userId,
+		EventType: types.AuditTokenRevoked,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"scope": "all", "before": before, "reason": reason},
+	})
+
+	return nil
+}
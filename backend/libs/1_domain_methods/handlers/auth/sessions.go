@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type revokeOtherSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+func sessionRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	r.Route("/auth/sessions", func(r chi.Router) {
+		r.Use(middlewares.Authenticate(sctx))
+		r.Use(requireUserToken)
+
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			claims := reqCtx.GetAccessClaims()
+
+			list, err := sessions.List(reqCtx, claims.GetUserID(), claims.SessionID)
+			if err != nil {
+				reqCtx.Errorf("list sessions error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.WriteJSON(w, list)
+		})
+
+		r.Delete("/{sessionId}", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			claims := reqCtx.GetAccessClaims()
+			sessionId := chi.URLParam(r, "sessionId")
+
+			err := sessions.Revoke(reqCtx, claims.GetUserID(), sessionId)
+			if errors.Is(err, sessions.ErrSessionNotFound) {
+				http.Error(w, "session not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				reqCtx.Errorf("revoke session error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			// выход из текущей сессии гасит и access-токен запроса, чужие доживают свой короткий срок
+			if sessionId == claims.SessionID {
+				if err := reqCtx.GetTokenService().Revoke(r.Context(), claims, "session revoked"); err != nil {
+					reqCtx.Errorf("revoke token error: %v", err)
+				}
+			}
+
+			helpers.Audit(reqCtx, r, types.AuditEvent{
+				UserID:    claims.GetUserID(),
+				EventType: types.AuditSessionRevoked,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"session_id": sessionId, "current": sessionId == claims.SessionID},
+			})
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Post("/revoke-others", func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := middlewares.RequestSmartContext(sctx, r)
+			claims := reqCtx.GetAccessClaims()
+
+			// без sid не понять, какую сессию оставить, — это токен, выпущенный до появления сессий
+			if claims.SessionID == "" {
+				http.Error(w, "access token has no session, sign in again", http.StatusBadRequest)
+				return
+			}
+
+			revoked, err := sessions.RevokeOthers(reqCtx, claims.GetUserID(), claims.SessionID)
+			if err != nil {
+				reqCtx.Errorf("revoke other sessions error: %v", err)
+				http.Error(w, "internal error", http.StatusInternalServerError)
+				return
+			}
+
+			helpers.Audit(reqCtx, r, types.AuditEvent{
+				UserID:    claims.GetUserID(),
+				EventType: types.AuditSessionRevoked,
+				Outcome:   types.AuditSuccess,
+				Details:   types.Fields{"scope": "others", "kept_session_id": claims.SessionID, "revoked": revoked},
+			})
+
+			helpers.WriteJSON(w, revokeOtherSessionsResponse{Revoked: revoked})
+		})
+	})
+}
@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const refreshTokenSeparator = "."
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenInvalid  = errors.New("invalid refresh token")
+)
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	// RefreshExpiresIn — сколько секунд действует refresh-токен, 0 — без срока
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+}
+
+// TokenGrant — кому и с какими правами выдаётся пара токенов. ClientID и Scope заполнены
+// для входа через OAuth2, AMR — способы входа. Всё это сохраняется в refresh-токене, чтобы
+// переноситься при обновлении
+type TokenGrant struct {
+	UserID   string
+	ClientID string
+	Scope    string
+	AMR      []string
+	// SessionID — сессия, которую продолжает /auth/refresh. Пустой — вход, начинается новая сессия
+	SessionID string
+	// DeviceName — имя устройства сессии, если клиент не прислал новое
+	DeviceName string
+	// SessionStartedAt — вход, с которого началась продолжаемая сессия
+	SessionStartedAt time.Time
+}
+
+// IssueTokenPair выпускает access-токен и сохраняет новый refresh-токен пользователя.
+// Роли и permissions читаются заново при каждом выпуске, так что изменения видны после /auth/refresh
+func IssueTokenPair(sctx smart_context.ISmartContext, grant TokenGrant, r *http.Request) (*TokenPair, error) {
+	clientIP := helpers.GetClientIP(r)
+
+	scope, roles, err := grantAccess(sctx, grant)
+	if err != nil {
+		return nil, fmt.Errorf("load user access error: %w", err)
+	}
+
+	// Refresh-токен: "<id строки>.<секрет base64>", id нужен, чтобы найти строку без перебора хэшей
+	refreshId, err := helpers.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("newUUID error: %w", err)
+	}
+
+	// первый токен сессии задаёт её id и начало отсчёта предельного срока
+	now := time.Now()
+	sessionId, startedAt := grant.SessionID, grant.SessionStartedAt
+	if sessionId == "" {
+		sessionId = refreshId
+	}
+	if startedAt.IsZero() {
+		startedAt = now
+	}
+	expiresAt := sessions.ExpiresAt(startedAt, now)
+
+	device := sessions.DeviceFromRequest(r)
+	if r.Header.Get(sessions.DeviceNameHeader) == "" && grant.DeviceName != "" {
+		device.Name = grant.DeviceName
+	}
+
+	accessToken, claims, err := sctx.GetTokenService().IssueAccessToken(types.AccessClaims{
+		Subject:   grant.UserID,
+		IP:        clientIP,
+		ClientID:  grant.ClientID,
+		Scope:     scope,
+		Roles:     roles,
+		AMR:       grant.AMR,
+		SessionID: sessionId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("issue access token error: %w", err)
+	}
+
+	refreshSecret, err := helpers.GenerateRandomBase64(32)
+	if err != nil {
+		return nil, fmt.Errorf("generateRandomBase64 error: %w", err)
+	}
+
+	hashedToken, err := bcrypt.GenerateFromPassword([]byte(refreshSecret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("bcrypt error: %w", err)
+	}
+
+	newRefresh := model.RefreshToken{
+		ID:          refreshId,
+		UserID:      grant.UserID,
+		HashedToken: string(hashedToken),
+		IPAddress:   clientIP,
+		Used:        false,
+		ClientID:    grant.ClientID,
+		Scope:       grant.Scope,
+		Amr:         strings.Join(grant.AMR, " "),
+		FamilyID:    sessionId,
+		UserAgent:   device.UserAgent,
+		DeviceName:  device.Name,
+		LastUsedAt:  now,
+
+		SessionStartedAt: startedAt,
+		ExpiresAt:        expiresAt,
+	}
+
+	omit := []string{"RevokedAt"}
+	if expiresAt.IsZero() {
+		omit = append(omit, "ExpiresAt")
+	}
+
+	var evicted []string
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		txCtx := sctx.WithDB(tx)
+		// новая сессия должна уложиться в лимит пользователя, обновление существующей его не проверяет
+		if grant.SessionID == "" {
+			var err error
+			if evicted, err = sessions.Admit(txCtx, grant.UserID); err != nil {
+				return err
+			}
+		}
+		return txCtx.GetDB().Omit(omit...).Create(&newRefresh).Error
+	})
+	if errors.Is(err, sessions.ErrSessionLimitReached) {
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    grant.UserID,
+			EventType: types.AuditSessionLimitReached,
+			Outcome:   types.AuditFailure,
+			Details:   types.Fields{"policy": string(sessions.LimitReject)},
+		})
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("DB error: %w", err)
+	}
+
+	for _, sessionId := range evicted {
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    grant.UserID,
+			EventType: types.AuditSessionRevoked,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"session_id": sessionId, "reason": "session limit"},
+		})
+	}
+
+	pair := &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshId + refreshTokenSeparator + refreshSecret,
+		ExpiresIn:    int64(time.Until(claims.ExpiresAt).Round(time.Second).Seconds()),
+		Scope:        scope,
+	}
+	if !expiresAt.IsZero() {
+		pair.RefreshExpiresIn = int64(expiresAt.Sub(now).Round(time.Second).Seconds())
+	}
+	return pair, nil
+}
+
+// writeIssueError отвечает на ошибку IssueTokenPair при входе
+func writeIssueError(sctx smart_context.ISmartContext, w http.ResponseWriter, err error) {
+	if errors.Is(err, sessions.ErrSessionLimitReached) {
+		http.Error(w, "active session limit reached, end one of the sessions first", http.StatusForbidden)
+		return
+	}
+
+	sctx.Errorf("issue token pair error: %v", err)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
+
+// grantAccess считает scope и роли токена. Собственные клиенты (без ClientID) получают все permissions
+// и роли пользователя, OAuth2-клиенты — только запрошенное, что пользователю доступно. Роли открывают
+// /admin и маршруты RequireRole, поэтому клиенту они достаются, только если запрошен scope roles
+func grantAccess(sctx smart_context.ISmartContext, grant TokenGrant) (string, []string, error) {
+	userAccess, err := access.LoadUserAccess(sctx, grant.UserID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if grant.ClientID == "" {
+		return strings.Join(userAccess.Permissions, " "), userAccess.Roles, nil
+	}
+
+	scope := access.FilterScope(grant.Scope, userAccess.Permissions)
+	if !slices.Contains(strings.Fields(scope), access.ScopeRoles) {
+		return scope, nil, nil
+	}
+	return scope, userAccess.Roles, nil
+}
+
+// FindRefreshToken находит строку refresh-токена и проверяет секрет. Найденная строка может быть
+// уже использованной — это решает вызывающий. userId пустой, если владелец заранее неизвестен;
+// токены старого формата без id тогда не ищутся
+func FindRefreshToken(sctx smart_context.ISmartContext, userId, refreshPlain string) (*model.RefreshToken, error) {
+	var ref model.RefreshToken
+
+	id, secret, ok := strings.Cut(refreshPlain, refreshTokenSeparator)
+	switch {
+	case ok:
+		q := sctx.GetDB().Where("id = ?", id)
+		if userId != "" {
+			q = q.Where("user_id = ?", userId)
+		}
+		if err := q.First(&ref).Error; err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRefreshTokenNotFound, err)
+		}
+	case userId != "":
+		// токены, выданные до появления id в формате
+		secret = refreshPlain
+		if err := sctx.GetDB().Where("user_id = ? AND used = false", userId).Last(&ref).Error; err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRefreshTokenNotFound, err)
+		}
+	default:
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(ref.HashedToken), []byte(secret)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRefreshTokenInvalid, err)
+	}
+
+	return &ref, nil
+}
+
+// markRefreshTokenUsed атомарно помечает токен использованным. false — его уже использовал параллельный запрос
+func markRefreshTokenUsed(sctx smart_context.ISmartContext, ref *model.RefreshToken) (bool, error) {
+	result := sctx.GetDB().Model(&model.RefreshToken{}).
+		Where("id = ? AND used = false", ref.ID).
+		Update("used", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	ref.Used = true
+	return result.RowsAffected > 0, nil
+}
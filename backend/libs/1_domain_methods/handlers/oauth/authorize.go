@@ -0,0 +1,266 @@
+package oauth
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"test-task3/libs/1_domain_methods/email_verification"
+	"test-task3/libs/1_domain_methods/handlers/auth"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/mfa"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+)
+
+type authorizeRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+}
+
+type loginPage struct {
+	ClientName string
+	Request    *authorizeRequest
+	Email      string
+	Error      string
+	// MFAToken заполнен на втором шаге: пароль принят, нужен код из приложения
+	MFAToken string
+}
+
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Sign in to {{.ClientName}}</title>
+</head>
+<body>
+<h1>Sign in to {{.ClientName}}</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<form method="post" action="/oauth/authorize">
+<input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+<input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+<input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+<input type="hidden" name="scope" value="{{.Request.Scope}}">
+<input type="hidden" name="state" value="{{.Request.State}}">
+<input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+<input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+<input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+{{if .MFAToken}}<input type="hidden" name="mfa_token" value="{{.MFAToken}}">
+<p><label>Authentication code <input type="text" name="code" inputmode="numeric" autocomplete="one-time-code" required autofocus></label></p>
+<p>Lost your device? Enter one of your recovery codes instead.</p>
+<p><button type="submit">Verify</button></p>
+{{else}}<p><label>Email <input type="email" name="email" value="{{.Email}}" autocomplete="username" required autofocus></label></p>
+<p><label>Password <input type="password" name="password" autocomplete="current-password" required></label></p>
+<p><button type="submit">Sign in</button></p>
+{{end}}
+</form>
+</body>
+</html>
+`))
+
+func parseAuthorizeRequest(r *http.Request) *authorizeRequest {
+	return &authorizeRequest{
+		ClientID:            r.Form.Get("client_id"),
+		RedirectURI:         r.Form.Get("redirect_uri"),
+		ResponseType:        r.Form.Get("response_type"),
+		Scope:               r.Form.Get("scope"),
+		State:               r.Form.Get("state"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+		Nonce:               r.Form.Get("nonce"),
+	}
+}
+
+// validateAuthorizeRequest возвращает ErrClientNotFound/ErrInvalidRedirectURI, если вернуть ошибку клиенту
+// через redirect_uri нельзя (RFC 6749, 4.1.2.1), и *oauthError для всех остальных ошибок запроса
+func validateAuthorizeRequest(sctx smart_context.ISmartContext, req *authorizeRequest) (*model.Client, error) {
+	client, err := FindClient(sctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRedirectURI(client, req.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return client, newOAuthError(errUnsupportedResponseType, "only response_type=code is supported")
+	}
+	// PKCE обязателен для всех клиентов, plain не принимаем
+	if req.CodeChallenge == "" {
+		return client, newOAuthError(errInvalidRequest, "code_challenge is required")
+	}
+	if req.CodeChallengeMethod != codeChallengeMethodS256 {
+		return client, newOAuthError(errInvalidRequest, "code_challenge_method must be S256")
+	}
+	if !isPKCEValue(req.CodeChallenge) {
+		return client, newOAuthError(errInvalidRequest, "invalid code_challenge")
+	}
+
+	scope, ok := grantScope(client, req.Scope)
+	if !ok {
+		return client, newOAuthError(errInvalidScope, "requested scope is not allowed for this client")
+	}
+	req.Scope = scope
+
+	return client, nil
+}
+
+func authorizeHandler(sctx smart_context.ISmartContext, cfg Config, lockoutCfg lockout.Config, mfaCfg mfa.Config, verificationCfg email_verification.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		req := parseAuthorizeRequest(r)
+		client, err := validateAuthorizeRequest(sctx, req)
+
+		var oauthErr *oauthError
+		switch {
+		case errors.As(err, &oauthErr):
+			redirectWithError(w, r, req, oauthErr)
+			return
+		case errors.Is(err, ErrClientNotFound), errors.Is(err, ErrInvalidRedirectURI):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		case err != nil:
+			sctx.Errorf("validate authorize request error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		page := loginPage{ClientName: client.Name, Request: req}
+		if r.Method == http.MethodGet {
+			renderLoginPage(sctx, w, http.StatusOK, page)
+			return
+		}
+
+		userId, amr, ok := authenticateUser(sctx, w, r, lockoutCfg, mfaCfg, verificationCfg, req, &page)
+		if !ok {
+			return
+		}
+
+		code, err := createAuthorizationCode(sctx, req, userId, amr, cfg.CodeTTL)
+		if err != nil {
+			sctx.Errorf("create authorization code error: %v", err)
+			redirectWithError(w, r, req, newOAuthError(errServerError, ""))
+			return
+		}
+
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    userId,
+			EventType: types.AuditAuthorizationCodeIssued,
+			Outcome:   types.AuditSuccess,
+			Details:   types.Fields{"client_id": client.ID, "scope": req.Scope},
+		})
+
+		redirectWithParams(w, r, req.RedirectURI, url.Values{
+			"code":  {code},
+			"state": {req.State},
+		})
+	}
+}
+
+// authenticateUser проводит вход на странице авторизации: пароль, затем код, если у пользователя включён TOTP.
+// false — ответ уже записан (страница с ошибкой, следующим шагом или redirect)
+func authenticateUser(sctx smart_context.ISmartContext, w http.ResponseWriter, r *http.Request, lockoutCfg lockout.Config, mfaCfg mfa.Config, verificationCfg email_verification.Config, req *authorizeRequest, page *loginPage) (string, []string, bool) {
+	if mfaToken := r.PostForm.Get("mfa_token"); mfaToken != "" {
+		page.MFAToken = mfaToken
+
+		code := r.PostForm.Get("code")
+		if code == "" {
+			page.Error = "Enter the code from your authenticator app."
+			renderLoginPage(sctx, w, http.StatusBadRequest, *page)
+			return "", nil, false
+		}
+
+		userId, amr, err := auth.CompleteMFA(sctx, mfaCfg, lockoutCfg, r, mfaToken, code)
+		if err == nil {
+			return userId, amr, true
+		}
+
+		switch {
+		case errors.Is(err, mfa.ErrInvalidCode):
+			page.Error = "Invalid code."
+			renderLoginPage(sctx, w, http.StatusUnauthorized, *page)
+		case errors.Is(err, mfa.ErrChallengeInvalid):
+			page.MFAToken = ""
+			page.Error = "Your sign-in has expired, enter your email and password again."
+			renderLoginPage(sctx, w, http.StatusUnauthorized, *page)
+		default:
+			writeAuthenticateError(sctx, w, r, req, page, err)
+		}
+		return "", nil, false
+	}
+
+	page.Email = r.PostForm.Get("email")
+	password := r.PostForm.Get("password")
+	if page.Email == "" || password == "" {
+		page.Error = "Enter your email and password."
+		renderLoginPage(sctx, w, http.StatusBadRequest, *page)
+		return "", nil, false
+	}
+
+	user, err := auth.AuthenticatePassword(sctx, lockoutCfg, verificationCfg, r, page.Email, password)
+	if err != nil {
+		writeAuthenticateError(sctx, w, r, req, page, err)
+		return "", nil, false
+	}
+
+	mfaToken, required, err := auth.StartMFA(sctx, mfaCfg, r, user.ID, []string{types.AMRPassword})
+	if err != nil {
+		sctx.Errorf("start mfa error: %v", err)
+		redirectWithError(w, r, req, newOAuthError(errServerError, ""))
+		return "", nil, false
+	}
+	if required {
+		page.MFAToken = mfaToken
+		renderLoginPage(sctx, w, http.StatusOK, *page)
+		return "", nil, false
+	}
+
+	return user.ID, []string{types.AMRPassword}, true
+}
+
+func writeAuthenticateError(sctx smart_context.ISmartContext, w http.ResponseWriter, r *http.Request, req *authorizeRequest, page *loginPage, err error) {
+	if _, ok := lockout.IsLocked(err); ok {
+		page.Error = "Too many failed login attempts, try again later."
+		renderLoginPage(sctx, w, http.StatusTooManyRequests, *page)
+		return
+	}
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		page.Error = "Invalid email or password."
+		renderLoginPage(sctx, w, http.StatusUnauthorized, *page)
+		return
+	}
+	if errors.Is(err, email_verification.ErrEmailNotVerified) {
+		page.Error = "Confirm your email address before signing in."
+		renderLoginPage(sctx, w, http.StatusForbidden, *page)
+		return
+	}
+
+	sctx.Errorf("authorize login error: %v", err)
+	redirectWithError(w, r, req, newOAuthError(errServerError, ""))
+}
+
+func renderLoginPage(sctx smart_context.ISmartContext, w http.ResponseWriter, status int, page loginPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// страница с паролем не должна встраиваться в чужие фреймы
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+	w.WriteHeader(status)
+
+	if err := loginTemplate.Execute(w, page); err != nil {
+		sctx.Errorf("render login page error: %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+package oauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrClientNotFound     = errors.New("client not found")
+	ErrInvalidClient      = errors.New("invalid client")
+	ErrInvalidRedirectURI = errors.New("invalid redirect_uri")
+	ErrPublicClient       = errors.New("client is public and has no secret")
+)
+
+// NewClient — параметры регистрации клиента. Пустой ID генерируется.
+// Confidential-клиент получает секрет и может использовать client_credentials,
+// AccessTokenTTLSeconds 0 — время жизни токена по умолчанию (JWT_ACCESS_TTL_SECONDS), больший JWT_MAX_ACCESS_TTL_SECONDS урезается
+type NewClient struct {
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name"`
+	RedirectURIs          []string `json:"redirect_uris"`
+	Confidential          bool     `json:"confidential"`
+	AllowedScopes         []string `json:"allowed_scopes"`
+	AccessTokenTTLSeconds int      `json:"access_token_ttl_seconds"`
+}
+
+// ClientView — клиент в ответах админского API и CLI, без хэшей секретов
+type ClientView struct {
+	ID                      string     `json:"id"`
+	Name                    string     `json:"name"`
+	RedirectURIs            []string   `json:"redirect_uris"`
+	Confidential            bool       `json:"confidential"`
+	AllowedScopes           []string   `json:"allowed_scopes"`
+	AccessTokenTTLSeconds   int        `json:"access_token_ttl_seconds"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+}
+
+// ClientWithSecret — ответ на создание клиента и ротацию секрета. Секрет показывается только один раз
+type ClientWithSecret struct {
+	ClientView
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// CreateClient регистрирует клиента. redirect_uri сравниваются при авторизации точно, поэтому сохраняются как есть
+func CreateClient(sctx smart_context.ISmartContext, req NewClient) (*ClientWithSecret, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: missing name", ErrInvalidClient)
+	}
+	if !req.Confidential && len(req.RedirectURIs) == 0 {
+		return nil, fmt.Errorf("%w: public client needs redirect_uris", ErrInvalidClient)
+	}
+	if req.AccessTokenTTLSeconds < 0 {
+		return nil, fmt.Errorf("%w: negative access_token_ttl_seconds", ErrInvalidClient)
+	}
+	for _, uri := range req.RedirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return nil, err
+		}
+	}
+	for _, scope := range req.AllowedScopes {
+		if scope == "" || strings.ContainsAny(scope, " \t\n\"\\") {
+			return nil, fmt.Errorf("%w: invalid scope %q", ErrInvalidClient, scope)
+		}
+	}
+
+	if req.ID == "" {
+		id, err := helpers.GenerateRandomHex(16)
+		if err != nil {
+			return nil, fmt.Errorf("generateRandomHex error: %w", err)
+		}
+		req.ID = id
+	}
+
+	client := model.Client{
+		ID:                    req.ID,
+		Name:                  strings.TrimSpace(req.Name),
+		RedirectUris:          strings.Join(req.RedirectURIs, " "),
+		AllowedScopes:         strings.Join(req.AllowedScopes, " "),
+		AccessTokenTTLSeconds: int32(req.AccessTokenTTLSeconds),
+	}
+
+	var secret string
+	if req.Confidential {
+		var err error
+		if secret, err = newClientSecret(); err != nil {
+			return nil, err
+		}
+		client.SecretHash = helpers.HashToken(secret)
+	}
+
+	if err := sctx.GetDB().Create(&client).Error; err != nil {
+		return nil, fmt.Errorf("DB error: %w", err)
+	}
+
+	return &ClientWithSecret{ClientView: newClientView(&client), ClientSecret: secret}, nil
+}
+
+// RotateClientSecret выпускает новый секрет. Старый продолжает приниматься ещё overlap,
+// чтобы сервисы успели переключиться; секрет, сменённый до этого, перестаёт работать сразу
+func RotateClientSecret(sctx smart_context.ISmartContext, clientId string, overlap time.Duration) (*ClientWithSecret, error) {
+	client, err := FindClient(sctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+	if client.SecretHash == "" {
+		return nil, ErrPublicClient
+	}
+
+	secret, err := newClientSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	client.PreviousSecretHash = client.SecretHash
+	client.PreviousSecretExpiresAt = now.Add(overlap)
+	client.SecretHash = helpers.HashToken(secret)
+	client.UpdatedAt = now
+
+	err = sctx.GetDB().Model(&model.Client{}).Where("id = ?", client.ID).Updates(map[string]interface{}{
+		"previous_secret_hash":       client.PreviousSecretHash,
+		"previous_secret_expires_at": client.PreviousSecretExpiresAt,
+		"secret_hash":                client.SecretHash,
+		"updated_at":                 client.UpdatedAt,
+	}).Error
+	if err != nil {
+		return nil, fmt.Errorf("DB error: %w", err)
+	}
+
+	return &ClientWithSecret{ClientView: newClientView(client), ClientSecret: secret}, nil
+}
+
+// DeleteClient удаляет клиента вместе с его неиспользованными кодами авторизации.
+// Уже выданные access-токены живут до истечения
+func DeleteClient(sctx smart_context.ISmartContext, clientId string) error {
+	result := sctx.GetDB().Where("id = ?", clientId).Delete(&model.Client{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrClientNotFound
+	}
+	return nil
+}
+
+func ListClients(sctx smart_context.ISmartContext) ([]ClientView, error) {
+	var clients []model.Client
+	if err := sctx.GetDB().Order("created_at").Find(&clients).Error; err != nil {
+		return nil, err
+	}
+
+	views := make([]ClientView, 0, len(clients))
+	for i := range clients {
+		views = append(views, newClientView(&clients[i]))
+	}
+	return views, nil
+}
+
+func FindClient(sctx smart_context.ISmartContext, clientId string) (*model.Client, error) {
+	if clientId == "" {
+		return nil, ErrClientNotFound
+	}
+
+	var client model.Client
+	err := sctx.GetDB().Where("id = ?", clientId).First(&client).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrClientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// verifyClientSecret сверяет секрет с текущим и, пока не истекло окно ротации, с предыдущим.
+// У публичного клиента секрета нет, и предъявлять его нельзя
+func verifyClientSecret(sctx smart_context.ISmartContext, client *model.Client, secret string) bool {
+	if client.SecretHash == "" {
+		return secret == ""
+	}
+	if secret == "" {
+		return false
+	}
+
+	hash := []byte(helpers.HashToken(secret))
+	if subtle.ConstantTimeCompare(hash, []byte(client.SecretHash)) == 1 {
+		return true
+	}
+
+	if client.PreviousSecretHash != "" && time.Now().Before(client.PreviousSecretExpiresAt) &&
+		subtle.ConstantTimeCompare(hash, []byte(client.PreviousSecretHash)) == 1 {
+		sctx.Warnf("Client %s authenticated with previous secret, it expires at %s", client.ID, client.PreviousSecretExpiresAt.Format(time.RFC3339))
+		return true
+	}
+	return false
+}
+
+// grantScope проверяет запрошенные scope по списку разрешённых клиенту. Пустой запрос — все разрешённые
+func grantScope(client *model.Client, requested string) (string, bool) {
+	allowed := strings.Fields(client.AllowedScopes)
+
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		return strings.Join(allowed, " "), true
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return "", false
+		}
+	}
+	return strings.Join(scopes, " "), true
+}
+
+// hasRedirectURI — только точное совпадение с зарегистрированным адресом, без префиксов и шаблонов
+func hasRedirectURI(client *model.Client, redirectURI string) bool {
+	return slices.Contains(strings.Fields(client.RedirectUris), redirectURI)
+}
+
+// validateRedirectURI допускает абсолютные адреса, в том числе собственные схемы мобильных приложений, но без фрагмента
+func validateRedirectURI(redirectURI string) error {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Scheme == "" || u.Fragment != "" || strings.ContainsAny(redirectURI, " \t\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidRedirectURI, redirectURI)
+	}
+	if (u.Scheme == "http" || u.Scheme == "https") && u.Host == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidRedirectURI, redirectURI)
+	}
+	return nil
+}
+
+func newClientSecret() (string, error) {
+	secret, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return "", fmt.Errorf("generateRandomHex error: %w", err)
+	}
+	return secret, nil
+}
+
+func newClientView(client *model.Client) ClientView {
+	view := ClientView{
+		ID:                    client.ID,
+		Name:                  client.Name,
+		RedirectURIs:          strings.Fields(client.RedirectUris),
+		Confidential:          client.SecretHash != "",
+		AllowedScopes:         strings.Fields(client.AllowedScopes),
+		AccessTokenTTLSeconds: int(client.AccessTokenTTLSeconds),
+		CreatedAt:             client.CreatedAt,
+	}
+	if client.PreviousSecretHash != "" && time.Now().Before(client.PreviousSecretExpiresAt) {
+		expiresAt := client.PreviousSecretExpiresAt
+		view.PreviousSecretExpiresAt = &expiresAt
+	}
+	return view
+}
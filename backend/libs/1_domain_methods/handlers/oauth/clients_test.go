@@ -0,0 +1,129 @@
+package oauth
+
+import (
+	"errors"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/test_db"
+	"testing"
+	"time"
+)
+
+func TestVerifyClientSecret(t *testing.T) {
+	sctx := smart_context.NewSmartContext()
+
+	confidential := func(previousExpiresAt time.Time) *model.Client {
+		return &model.Client{
+			ID:                      "orders-api",
+			SecretHash:              helpers.HashToken("current-secret"),
+			PreviousSecretHash:      helpers.HashToken("previous-secret"),
+			PreviousSecretExpiresAt: previousExpiresAt,
+		}
+	}
+	inOverlap := time.Now().Add(time.Hour)
+	afterOverlap := time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name   string
+		client *model.Client
+		secret string
+		want   bool
+	}{
+		{"public without secret", &model.Client{ID: "spa"}, "", true},
+		// публичный клиент не может выдать себя за конфиденциальный и наоборот
+		{"public with secret", &model.Client{ID: "spa"}, "anything", false},
+		{"current secret", confidential(inOverlap), "current-secret", true},
+		{"missing secret", confidential(inOverlap), "", false},
+		{"wrong secret", confidential(inOverlap), "wrong-secret", false},
+		{"previous secret during overlap", confidential(inOverlap), "previous-secret", true},
+		{"previous secret after overlap", confidential(afterOverlap), "previous-secret", false},
+		{"current secret after overlap", confidential(afterOverlap), "current-secret", true},
+		{"no previous secret", &model.Client{ID: "orders-api", SecretHash: helpers.HashToken("current-secret"), PreviousSecretExpiresAt: inOverlap}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyClientSecret(sctx, tt.client, tt.secret); got != tt.want {
+				t.Errorf("verifyClientSecret(%q) = %v, want %v", tt.secret, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateClientSecret(t *testing.T) {
+	db := test_db.Open(t, "clients")
+	sctx := smart_context.NewSmartContext().WithDB(db)
+
+	created, err := CreateClient(sctx, NewClient{ID: "orders-api", Name: "Orders API", Confidential: true})
+	if err != nil {
+		t.Fatalf("CreateClient() error = %v", err)
+	}
+	first, err := RotateClientSecret(sctx, "orders-api", time.Hour)
+	if err != nil {
+		t.Fatalf("RotateClientSecret() error = %v", err)
+	}
+
+	client, err := FindClient(sctx, "orders-api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// оба секрета принимаются, пока сервисы переключаются
+	if !verifyClientSecret(sctx, client, first.ClientSecret) || !verifyClientSecret(sctx, client, created.ClientSecret) {
+		t.Error("new and previous secrets must both work during the overlap")
+	}
+
+	// повторная ротация сразу отключает самый старый секрет
+	second, err := RotateClientSecret(sctx, "orders-api", time.Hour)
+	if err != nil {
+		t.Fatalf("RotateClientSecret() error = %v", err)
+	}
+	if client, err = FindClient(sctx, "orders-api"); err != nil {
+		t.Fatal(err)
+	}
+	if verifyClientSecret(sctx, client, created.ClientSecret) {
+		t.Error("secret replaced two rotations ago still works")
+	}
+	if !verifyClientSecret(sctx, client, first.ClientSecret) || !verifyClientSecret(sctx, client, second.ClientSecret) {
+		t.Error("new and previous secrets must both work during the overlap")
+	}
+
+	if _, err := CreateClient(sctx, NewClient{ID: "spa", Name: "SPA", RedirectURIs: []string{"https://app.example.com/cb"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RotateClientSecret(sctx, "spa", time.Hour); !errors.Is(err, ErrPublicClient) {
+		t.Errorf("RotateClientSecret(public) error = %v, want %v", err, ErrPublicClient)
+	}
+}
+
+func TestGrantScope(t *testing.T) {
+	client := &model.Client{AllowedScopes: "openid email orders:read"}
+
+	tests := []struct {
+		name      string
+		requested string
+		want      string
+		wantOK    bool
+	}{
+		{"empty gets all allowed", "", "openid email orders:read", true},
+		{"whitespace only", "  ", "openid email orders:read", true},
+		{"subset", "openid orders:read", "openid orders:read", true},
+		{"extra spaces", " openid   email ", "openid email", true},
+		{"not allowed", "openid orders:write", "", false},
+		{"prefix of allowed", "orders", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := grantScope(client, tt.requested)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("grantScope(%q) = %q, %v, want %q, %v", tt.requested, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// клиенту без allowed_scopes нельзя запросить ничего
+	if got, ok := grantScope(&model.Client{}, "openid"); ok {
+		t.Errorf("grantScope() without allowed scopes = %q, %v, want rejection", got, ok)
+	}
+}
@@ -0,0 +1,120 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const codeChallengeMethodS256 = "S256"
+
+var (
+	errCodeNotFound = errors.New("authorization code not found")
+	errCodeReused   = errors.New("authorization code already used")
+)
+
+// createAuthorizationCode сохраняет хэш кода и возвращает сам код — он уходит клиенту в redirect_uri.
+// amr — способы, которыми пользователь только что вошёл, они попадут в ID-токен
+func createAuthorizationCode(sctx smart_context.ISmartContext, req *authorizeRequest, userId string, amr []string, ttl time.Duration) (string, error) {
+	code, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return "", fmt.Errorf("generateRandomHex error: %w", err)
+	}
+
+	authCode := model.AuthorizationCode{
+		CodeHash:            helpers.HashToken(code),
+		ClientID:            req.ClientID,
+		UserID:              userId,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Nonce:               req.Nonce,
+		AuthTime:            time.Now(),
+		Amr:                 strings.Join(amr, " "),
+		ExpiresAt:           time.Now().Add(ttl),
+	}
+	if err := sctx.GetDB().Create(&authCode).Error; err != nil {
+		return "", fmt.Errorf("DB error: %w", err)
+	}
+
+	return code, nil
+}
+
+func findAuthorizationCode(sctx smart_context.ISmartContext, code string) (*model.AuthorizationCode, error) {
+	var authCode model.AuthorizationCode
+	err := sctx.GetDB().Where("code_hash = ?", helpers.HashToken(code)).First(&authCode).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &authCode, nil
+}
+
+// markAuthorizationCodeUsed атомарно гасит код. false — его уже обменял параллельный запрос
+func markAuthorizationCodeUsed(sctx smart_context.ISmartContext, authCode *model.AuthorizationCode) (bool, error) {
+	result := sctx.GetDB().Model(&model.AuthorizationCode{}).
+		Where("id = ? AND used = false", authCode.ID).
+		Update("used", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	authCode.Used = true
+	return result.RowsAffected > 0, nil
+}
+
+// checkAuthorizationCode проверяет найденный код перед обменом (RFC 6749, 4.1.3). Для уже обменянного кода
+// возвращает errCodeReused — вызывающий отзывает выданные по нему токены, остальные отказы — invalid_grant
+func checkAuthorizationCode(authCode *model.AuthorizationCode, clientId, redirectURI, verifier string, now time.Time) error {
+	switch {
+	case authCode.Used:
+		return errCodeReused
+	case now.After(authCode.ExpiresAt):
+		return newOAuthError(errInvalidGrant, "authorization code expired")
+	case authCode.ClientID != clientId:
+		return newOAuthError(errInvalidGrant, "authorization code was issued to another client")
+	case authCode.RedirectURI != redirectURI:
+		return newOAuthError(errInvalidGrant, "redirect_uri does not match")
+	case !verifyPKCE(authCode, verifier):
+		return newOAuthError(errInvalidGrant, "code_verifier does not match code_challenge")
+	}
+	return nil
+}
+
+// verifyPKCE проверяет code_verifier против сохранённого S256 code_challenge (RFC 7636, 4.6)
+func verifyPKCE(authCode *model.AuthorizationCode, verifier string) bool {
+	if authCode.CodeChallengeMethod != codeChallengeMethodS256 || !isPKCEValue(verifier) {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(authCode.CodeChallenge)) == 1
+}
+
+// isPKCEValue — 43..128 символов из набора unreserved (RFC 7636, 4.1). Тот же формат годится и для S256 challenge
+func isPKCEValue(value string) bool {
+	if len(value) < 43 || len(value) > 128 {
+		return false
+	}
+	for _, c := range value {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-', c == '.', c == '_', c == '~':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package oauth
+
+import (
+	"errors"
+	"strings"
+	"test-task3/libs/2_generated_models/model"
+	"testing"
+	"time"
+)
+
+// пример из RFC 7636, приложение B
+const (
+	rfcVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfcChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestVerifyPKCE(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		verifier string
+		want     bool
+	}{
+		{"rfc 7636 appendix b", codeChallengeMethodS256, rfcVerifier, true},
+		{"wrong verifier", codeChallengeMethodS256, strings.Replace(rfcVerifier, "d", "e", 1), false},
+		// plain не поддерживается: сравнение verifier с challenge напрямую не должно проходить
+		{"plain method", "plain", rfcChallenge, false},
+		{"empty method", "", rfcVerifier, false},
+		{"short verifier", codeChallengeMethodS256, rfcVerifier[:42], false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authCode := &model.AuthorizationCode{CodeChallenge: rfcChallenge, CodeChallengeMethod: tt.method}
+			if got := verifyPKCE(authCode, tt.verifier); got != tt.want {
+				t.Errorf("verifyPKCE() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsPKCEValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{rfcVerifier, true},
+		{strings.Repeat("a", 43), true},
+		{strings.Repeat("a", 128), true},
+		{strings.Repeat("a", 42), false},
+		{strings.Repeat("a", 129), false},
+		{strings.Repeat("a", 42) + "-._~", true},
+		{strings.Repeat("a", 42) + "+", false},
+		{strings.Repeat("a", 42) + "=", false},
+		{strings.Repeat("a", 42) + "é", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPKCEValue(tt.value); got != tt.want {
+			t.Errorf("isPKCEValue(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAuthorizationCode(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	valid := model.AuthorizationCode{
+		ClientID:            "client-1",
+		RedirectURI:         "https://app.example.com/callback",
+		CodeChallenge:       rfcChallenge,
+		CodeChallengeMethod: codeChallengeMethodS256,
+		ExpiresAt:           now.Add(time.Minute),
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(c *model.AuthorizationCode)
+		clientId    string
+		redirectURI string
+		verifier    string
+		wantErr     string
+	}{
+		{"valid", nil, "client-1", valid.RedirectURI, rfcVerifier, ""},
+		{"replayed code", func(c *model.AuthorizationCode) { c.Used = true }, "client-1", valid.RedirectURI, rfcVerifier, "reused"},
+		{"expired", func(c *model.AuthorizationCode) { c.ExpiresAt = now.Add(-time.Second) }, "client-1", valid.RedirectURI, rfcVerifier, errInvalidGrant},
+		{"another client", nil, "client-2", valid.RedirectURI, rfcVerifier, errInvalidGrant},
+		{"redirect_uri mismatch", nil, "client-1", "https://app.example.com/callback/", rfcVerifier, errInvalidGrant},
+		{"missing redirect_uri", nil, "client-1", "", rfcVerifier, errInvalidGrant},
+		{"wrong verifier", nil, "client-1", valid.RedirectURI, strings.Repeat("a", 43), errInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authCode := valid
+			if tt.modify != nil {
+				tt.modify(&authCode)
+			}
+
+			err := checkAuthorizationCode(&authCode, tt.clientId, tt.redirectURI, tt.verifier, now)
+
+			var oauthErr *oauthError
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("checkAuthorizationCode() error = %v, want nil", err)
+			case tt.wantErr == "reused" && !errors.Is(err, errCodeReused):
+				t.Errorf("checkAuthorizationCode() error = %v, want %v", err, errCodeReused)
+			case tt.wantErr == errInvalidGrant && (!errors.As(err, &oauthErr) || oauthErr.Code != errInvalidGrant):
+				t.Errorf("checkAuthorizationCode() error = %v, want %s", err, errInvalidGrant)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// коды ошибок RFC 6749 (разделы 4.1.2.1 и 5.2)
+const (
+	errInvalidRequest          = "invalid_request"
+	errInvalidClient           = "invalid_client"
+	errInvalidGrant            = "invalid_grant"
+	errUnauthorizedClient      = "unauthorized_client"
+	errUnsupportedGrantType    = "unsupported_grant_type"
+	errUnsupportedResponseType = "unsupported_response_type"
+	errInvalidScope            = "invalid_scope"
+	errServerError             = "server_error"
+)
+
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *oauthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func newOAuthError(code, description string) *oauthError {
+	return &oauthError{Code: code, Description: description}
+}
+
+// writeTokenError отвечает на ошибку /oauth/token JSON-ом. invalid_client — 401, остальные — 400
+func writeTokenError(w http.ResponseWriter, oauthErr *oauthError) {
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case errInvalidClient:
+		status = http.StatusUnauthorized
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	case errServerError:
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(oauthErr)
+}
+
+// redirectWithParams возвращает браузер на проверенный redirect_uri клиента с параметрами ответа
+func redirectWithParams(w http.ResponseWriter, r *http.Request, redirectURI string, params url.Values) {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		for _, value := range values {
+			if value != "" {
+				query.Add(key, value)
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+func redirectWithError(w http.ResponseWriter, r *http.Request, req *authorizeRequest, oauthErr *oauthError) {
+	redirectWithParams(w, r, req.RedirectURI, url.Values{
+		"error":             {oauthErr.Code},
+		"error_description": {oauthErr.Description},
+		"state":             {req.State},
+	})
+}
@@ -0,0 +1,186 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"test-task3/libs/1_domain_methods/api_keys"
+	"test-task3/libs/1_domain_methods/handlers/auth"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	tokenTypeHintAccess  = "access_token"
+	tokenTypeHintRefresh = "refresh_token"
+
+	// scopeIntrospect — permission API-ключа или разрешённый scope клиента, дающий доступ к /auth/introspect
+	scopeIntrospect = "tokens:introspect"
+)
+
+var errIntrospectionDenied = errors.New("introspection caller is not allowed")
+
+// introspectionResponse — ответ RFC 7662. Для неактивного токена возвращается только active=false
+type introspectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Exp       int64    `json:"exp,omitempty"`
+	Iat       int64    `json:"iat,omitempty"`
+	Nbf       int64    `json:"nbf,omitempty"`
+	Sub       string   `json:"sub,omitempty"`
+	Aud       []string `json:"aud,omitempty"`
+	Iss       string   `json:"iss,omitempty"`
+	Jti       string   `json:"jti,omitempty"`
+}
+
+func introspectRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	r.Post("/auth/introspect", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		callerId, err := authenticateIntrospectionCaller(sctx, r)
+		if errors.Is(err, errIntrospectionDenied) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+			http.Error(w, "invalid client", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			sctx.Errorf("authenticate introspection caller error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		token := r.PostForm.Get("token")
+		if token == "" {
+			http.Error(w, "missing token", http.StatusBadRequest)
+			return
+		}
+
+		// подсказка только задаёт порядок проверки (RFC 7662, 2.1)
+		resp := introspectionResponse{}
+		if r.PostForm.Get("token_type_hint") == tokenTypeHintRefresh {
+			resp = introspectRefreshToken(sctx, token)
+			if !resp.Active {
+				resp = introspectAccessToken(sctx, r, token)
+			}
+		} else {
+			resp = introspectAccessToken(sctx, r, token)
+			if !resp.Active {
+				resp = introspectRefreshToken(sctx, token)
+			}
+		}
+
+		sctx.Debugf("introspection by %s: active=%v sub=%s", callerId, resp.Active, resp.Sub)
+		helpers.WriteJSON(w, resp)
+	})
+}
+
+func introspectAccessToken(sctx smart_context.ISmartContext, r *http.Request, token string) introspectionResponse {
+	tokenService := sctx.GetTokenService()
+
+	claims, err := tokenService.ParseAccessToken(token)
+	if err != nil {
+		return introspectionResponse{}
+	}
+
+	revoked, err := tokenService.IsRevoked(r.Context(), claims)
+	if err != nil {
+		sctx.Errorf("introspection revocation check error: %v", err)
+		return introspectionResponse{}
+	}
+	if revoked {
+		return introspectionResponse{}
+	}
+
+	return introspectionResponse{
+		Active:    true,
+		TokenType: "Bearer",
+		Exp:       claims.ExpiresAt.Unix(),
+		Iat:       unixOrZero(claims.IssuedAt),
+		Nbf:       unixOrZero(claims.NotBefore),
+		Sub:       claims.GetUserID(),
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Aud:       claims.Audience,
+		Iss:       claims.Issuer,
+		Jti:       claims.ID,
+	}
+}
+
+func introspectRefreshToken(sctx smart_context.ISmartContext, token string) introspectionResponse {
+	ref, err := auth.FindRefreshToken(sctx, "", token)
+	if err != nil {
+		if !errors.Is(err, auth.ErrRefreshTokenNotFound) && !errors.Is(err, auth.ErrRefreshTokenInvalid) {
+			sctx.Errorf("introspection refresh lookup error: %v", err)
+		}
+		return introspectionResponse{}
+	}
+	if ref.Used || !ref.RevokedAt.IsZero() || sessions.CheckExpiry(ref, time.Now()) != nil {
+		return introspectionResponse{}
+	}
+
+	resp := introspectionResponse{
+		Active:    true,
+		TokenType: tokenTypeHintRefresh,
+		Scope:     ref.Scope,
+		ClientID:  ref.ClientID,
+		Iat:       ref.CreatedAt.Unix(),
+		Sub:       ref.UserID,
+	}
+	if expiresAt := sessions.TokenExpiresAt(ref); !expiresAt.IsZero() {
+		resp.Exp = expiresAt.Unix()
+	}
+	return resp
+}
+
+// unixOrZero — у токенов старого формата нет iat и nbf: нулевое время не превращается в отрицательный unix
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// authenticateIntrospectionCaller пускает конфиденциальных клиентов из таблицы clients (HTTP Basic или
+// client_id/client_secret в форме) и API-ключи (X-Api-Key) со scope tokens:introspect: ответ раскрывает
+// владельца и права любого токена, поэтому доступ выдаётся явно
+func authenticateIntrospectionCaller(sctx smart_context.ISmartContext, r *http.Request) (string, error) {
+	if key := helpers.APIKeyFromRequest(r); key != "" {
+		claims, err := api_keys.Authenticate(sctx, key)
+		switch {
+		case errors.Is(err, api_keys.ErrKeyNotFound), errors.Is(err, api_keys.ErrInvalidKey),
+			errors.Is(err, api_keys.ErrKeyExpired), errors.Is(err, api_keys.ErrKeyRevoked):
+			return "", errIntrospectionDenied
+		case err != nil:
+			return "", err
+		case !claims.HasScope(scopeIntrospect):
+			return "", errIntrospectionDenied
+		}
+		return "api_key:" + claims.APIKeyID, nil
+	}
+
+	client, err := authenticateClient(sctx, r)
+	var oauthErr *oauthError
+	if errors.As(err, &oauthErr) {
+		return "", errIntrospectionDenied
+	}
+	if err != nil {
+		return "", err
+	}
+	// публичный клиент проходит authenticateClient без секрета
+	if client.SecretHash == "" || !slices.Contains(strings.Fields(client.AllowedScopes), scopeIntrospect) {
+		return "", errIntrospectionDenied
+	}
+	return client.ID, nil
+}
@@ -0,0 +1,72 @@
+package oauth
+
+import (
+	"net/http"
+	"strings"
+	"test-task3/libs/1_domain_methods/email_verification"
+	"test-task3/libs/1_domain_methods/lockout"
+	"test-task3/libs/1_domain_methods/mfa"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/3_infrastructure/token_service"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Config struct {
+	CodeTTL time.Duration
+	// SecretOverlap — сколько после ротации ещё принимается старый секрет клиента
+	SecretOverlap time.Duration
+}
+
+// LoadConfig читает OAUTH_CODE_TTL_SECONDS (по умолчанию минута) и OAUTH_CLIENT_SECRET_OVERLAP_SECONDS (сутки)
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	return Config{
+		CodeTTL:       time.Duration(env_vars.GetEnvAsInt(sctx, "OAUTH_CODE_TTL_SECONDS", 60)) * time.Second,
+		SecretOverlap: time.Duration(env_vars.GetEnvAsInt(sctx, "OAUTH_CLIENT_SECRET_OVERLAP_SECONDS", 86400)) * time.Second,
+	}
+}
+
+func OAuthRoutes(r chi.Router, sctx smart_context.ISmartContext) {
+	cfg := LoadConfig(sctx)
+	lockoutCfg := lockout.LoadConfig(sctx)
+	mfaCfg := mfa.LoadConfig(sctx)
+	verificationCfg := email_verification.LoadConfig(sctx)
+
+	authorizeLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route:   "oauth_authorize",
+		PerIP:   "20/m",
+		PerUser: "10/m",
+		UserID: func(r *http.Request) string {
+			if r.Method != http.MethodPost || r.ParseForm() != nil {
+				return ""
+			}
+			return strings.ToLower(strings.TrimSpace(r.PostForm.Get("email")))
+		},
+	})
+
+	tokenLimit := middlewares.RateLimit(sctx, middlewares.RateLimitRule{
+		Route: "oauth_token",
+		PerIP: "30/m",
+	})
+
+	r.Route("/oauth", func(r chi.Router) {
+		r.With(authorizeLimit).Get("/authorize", authorizeHandler(sctx, cfg, lockoutCfg, mfaCfg, verificationCfg))
+		r.With(authorizeLimit).Post("/authorize", authorizeHandler(sctx, cfg, lockoutCfg, mfaCfg, verificationCfg))
+		r.With(tokenLimit).Post("/token", tokenHandler(sctx))
+	})
+
+	issuer := token_service.LoadConfig(sctx).Issuer
+	if !isURLIssuer(issuer) {
+		sctx.Warnf("JWT_ISSUER %q is not a URL, OpenID Connect discovery will not work for external clients", issuer)
+	}
+
+	introspectRoutes(r, sctx)
+
+	r.Get("/.well-known/openid-configuration", discoveryHandler(issuer))
+	r.Get("/.well-known/jwks.json", jwksHandler(sctx))
+	r.With(middlewares.Authenticate(sctx)).Get("/userinfo", userinfoHandler(sctx))
+	r.With(middlewares.Authenticate(sctx)).Post("/userinfo", userinfoHandler(sctx))
+}
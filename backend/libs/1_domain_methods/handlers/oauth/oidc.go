@@ -0,0 +1,155 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/middlewares"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"gorm.io/gorm"
+)
+
+// стандартные scope OpenID Connect (OIDC Core, 5.4)
+const (
+	scopeOpenID  = "openid"
+	scopeEmail   = "email"
+	scopeProfile = "profile"
+)
+
+type discoveryDocument struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+type userinfoResponse struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	UpdatedAt     int64  `json:"updated_at,omitempty"`
+}
+
+// discoveryHandler отдаёт /.well-known/openid-configuration. Адреса строятся от issuer,
+// поэтому JWT_ISSUER должен быть публичным URL сервиса
+func discoveryHandler(issuer string) http.HandlerFunc {
+	base := strings.TrimSuffix(issuer, "/")
+	doc := discoveryDocument{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             base + "/oauth/authorize",
+		TokenEndpoint:                     base + "/oauth/token",
+		UserinfoEndpoint:                  base + "/userinfo",
+		JwksURI:                           base + "/.well-known/jwks.json",
+		IntrospectionEndpoint:             base + "/auth/introspect",
+		ScopesSupported:                   []string{scopeOpenID, scopeEmail, scopeProfile, access.ScopeRoles},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{grantTypeAuthorizationCode, grantTypeClientCredentials, grantTypeRefreshToken},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{codeChallengeMethodS256},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "amr", "azp", "email", "email_verified", "updated_at"},
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		helpers.WriteJSON(w, doc)
+	}
+}
+
+func jwksHandler(sctx smart_context.ISmartContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		helpers.WriteJSON(w, sctx.GetTokenService().JWKS())
+	}
+}
+
+// userinfoHandler возвращает claims пользователя по access-токену со scope openid (OIDC Core, 5.3)
+func userinfoHandler(sctx smart_context.ISmartContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqCtx := middlewares.RequestSmartContext(sctx, r)
+		claims := reqCtx.GetAccessClaims()
+
+		if claims.IsClientToken() || !claims.HasScope(scopeOpenID) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="openid"`)
+			http.Error(w, "access token has no openid scope", http.StatusForbidden)
+			return
+		}
+
+		var user model.User
+		err := reqCtx.GetDB().Where("id = ?", claims.GetUserID()).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "user not found", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			reqCtx.Errorf("load user for userinfo error: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+
+		resp := userinfoResponse{Subject: user.ID}
+		if claims.HasScope(scopeEmail) {
+			emailVerified := !user.EmailVerifiedAt.IsZero()
+			resp.Email = user.Email
+			resp.EmailVerified = &emailVerified
+		}
+		if claims.HasScope(scopeProfile) && !user.UpdatedAt.IsZero() {
+			resp.UpdatedAt = user.UpdatedAt.Unix()
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		helpers.WriteJSON(w, resp)
+	}
+}
+
+// issueIDToken выпускает ID-токен для клиента, обменявшего код со scope openid
+func issueIDToken(sctx smart_context.ISmartContext, authCode *model.AuthorizationCode) (string, error) {
+	claims := types.IDTokenClaims{
+		Subject:         authCode.UserID,
+		Audience:        []string{authCode.ClientID},
+		AuthorizedParty: authCode.ClientID,
+		AuthTime:        authCode.AuthTime,
+		Nonce:           authCode.Nonce,
+		AMR:             strings.Fields(authCode.Amr),
+	}
+
+	if hasScope(authCode.Scope, scopeEmail) {
+		var user model.User
+		if err := sctx.GetDB().Where("id = ?", authCode.UserID).First(&user).Error; err != nil {
+			return "", err
+		}
+		claims.Email = user.Email
+		claims.EmailVerified = !user.EmailVerifiedAt.IsZero()
+	}
+
+	return sctx.GetTokenService().IssueIDToken(claims)
+}
+
+func hasScope(scope, name string) bool {
+	return slices.Contains(strings.Fields(scope), name)
+}
+
+// isURLIssuer — OIDC требует, чтобы issuer был https URL без query и фрагмента (http допустим для разработки)
+func isURLIssuer(issuer string) bool {
+	u, err := url.Parse(issuer)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" && u.RawQuery == "" && u.Fragment == ""
+}
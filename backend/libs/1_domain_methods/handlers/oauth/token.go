@@ -0,0 +1,297 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"test-task3/libs/1_domain_methods/handlers/auth"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/sessions"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+const (
+	grantTypeAuthorizationCode = "authorization_code"
+	grantTypeClientCredentials = "client_credentials"
+	grantTypeRefreshToken      = "refresh_token"
+)
+
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// RefreshExpiresIn — не из RFC 6749, как у Keycloak
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+	IDToken          string `json:"id_token,omitempty"`
+}
+
+func tokenHandler(sctx smart_context.ISmartContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			writeTokenError(w, newOAuthError(errInvalidRequest, "invalid request body"))
+			return
+		}
+
+		var (
+			resp *tokenResponse
+			err  error
+		)
+		switch grantType := r.PostForm.Get("grant_type"); grantType {
+		case grantTypeAuthorizationCode:
+			resp, err = exchangeAuthorizationCode(sctx, r)
+		case grantTypeClientCredentials:
+			resp, err = issueClientCredentialsToken(sctx, r)
+		case grantTypeRefreshToken:
+			resp, err = refreshTokens(sctx, r)
+		case "":
+			err = newOAuthError(errInvalidRequest, "missing grant_type")
+		default:
+			err = newOAuthError(errUnsupportedGrantType, "grant_type "+grantType+" is not supported")
+		}
+
+		var oauthErr *oauthError
+		if errors.As(err, &oauthErr) {
+			writeTokenError(w, oauthErr)
+			return
+		}
+		if err != nil {
+			sctx.Errorf("oauth token error: %v", err)
+			writeTokenError(w, newOAuthError(errServerError, ""))
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Pragma", "no-cache")
+		helpers.WriteJSON(w, resp)
+	}
+}
+
+// exchangeAuthorizationCode меняет код на ту же пару токенов, что выдаёт /auth (RFC 6749, 4.1.3 и RFC 7636, 4.5)
+func exchangeAuthorizationCode(sctx smart_context.ISmartContext, r *http.Request) (*tokenResponse, error) {
+	code := r.PostForm.Get("code")
+	verifier := r.PostForm.Get("code_verifier")
+	if code == "" || verifier == "" {
+		return nil, newOAuthError(errInvalidRequest, "code and code_verifier are required")
+	}
+
+	client, err := authenticateClient(sctx, r)
+	if err != nil {
+		return nil, err
+	}
+	clientId := client.ID
+
+	authCode, err := findAuthorizationCode(sctx, code)
+	if errors.Is(err, errCodeNotFound) {
+		return nil, newOAuthError(errInvalidGrant, "invalid authorization code")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkAuthorizationCode(authCode, clientId, r.PostForm.Get("redirect_uri"), verifier, time.Now())
+	if errors.Is(err, errCodeReused) {
+		// повторный обмен кода — вероятно, код перехвачен: отзываем выданные по нему токены вместе с остальными
+		sctx.Errorf("Authorization code %s reused by client %s", authCode.ID, clientId)
+		if err := auth.RevokeAllUserTokens(sctx, r, authCode.UserID, time.Now(), "authorization code reuse"); err != nil {
+			sctx.Errorf("revoke user tokens error: %v", err)
+		}
+		helpers.Audit(sctx, r, types.AuditEvent{
+			UserID:    authCode.UserID,
+			EventType: types.AuditAuthorizationCodeReuse,
+			Outcome:   types.AuditFailure,
+			Details:   types.Fields{"client_id": clientId, "authorization_code_id": authCode.ID},
+		})
+		return nil, newOAuthError(errInvalidGrant, "authorization code already used")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := markAuthorizationCodeUsed(sctx, authCode)
+	if err != nil {
+		return nil, err
+	}
+	if !marked {
+		return nil, newOAuthError(errInvalidGrant, "authorization code already used")
+	}
+
+	pair, err := auth.IssueTokenPair(sctx, auth.TokenGrant{
+		UserID:   authCode.UserID,
+		ClientID: clientId,
+		Scope:    authCode.Scope,
+		AMR:      strings.Fields(authCode.Amr),
+	}, r)
+	if errors.Is(err, sessions.ErrSessionLimitReached) {
+		return nil, newOAuthError(errInvalidGrant, "active session limit reached")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var idToken string
+	if hasScope(authCode.Scope, scopeOpenID) {
+		if idToken, err = issueIDToken(sctx, authCode); err != nil {
+			return nil, err
+		}
+	}
+
+	helpers.Audit(sctx, r, types.AuditEvent{
+		UserID:    authCode.UserID,
+		EventType: types.AuditTokenIssued,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"method": grantTypeAuthorizationCode, "client_id": clientId},
+	})
+
+	return &tokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    pair.ExpiresIn,
+		RefreshToken: pair.RefreshToken,
+		Scope:        pair.Scope,
+		IDToken:      idToken,
+
+		RefreshExpiresIn: pair.RefreshExpiresIn,
+	}, nil
+}
+
+// issueClientCredentialsToken выдаёт сервису access-токен без refresh-токена, sub — id клиента (RFC 6749, 4.4)
+func issueClientCredentialsToken(sctx smart_context.ISmartContext, r *http.Request) (*tokenResponse, error) {
+	client, err := authenticateClient(sctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if client.SecretHash == "" {
+		return nil, newOAuthError(errUnauthorizedClient, "public clients cannot use client_credentials")
+	}
+
+	scope, ok := grantScope(client, r.PostForm.Get("scope"))
+	if !ok {
+		return nil, newOAuthError(errInvalidScope, "requested scope is not allowed for this client")
+	}
+
+	claims := types.AccessClaims{
+		Subject:  client.ID,
+		ClientID: client.ID,
+		Scope:    scope,
+	}
+	if client.AccessTokenTTLSeconds > 0 {
+		claims.ExpiresAt = time.Now().Add(time.Duration(client.AccessTokenTTLSeconds) * time.Second)
+	}
+
+	accessToken, issued, err := sctx.GetTokenService().IssueAccessToken(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	helpers.Audit(sctx, r, types.AuditEvent{
+		EventType: types.AuditTokenIssued,
+		Outcome:   types.AuditSuccess,
+		Details:   types.Fields{"method": grantTypeClientCredentials, "client_id": client.ID, "scope": scope},
+	})
+
+	return &tokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(issued.ExpiresAt).Round(time.Second).Seconds()),
+		Scope:       scope,
+	}, nil
+}
+
+// refreshTokens меняет refresh-токен клиента на новую пару той же сессии (RFC 6749, 6). Ротация та же,
+// что у /auth/refresh: повторное использование отзывает все токены пользователя
+func refreshTokens(sctx smart_context.ISmartContext, r *http.Request) (*tokenResponse, error) {
+	refreshPlain := r.PostForm.Get("refresh_token")
+	if refreshPlain == "" {
+		return nil, newOAuthError(errInvalidRequest, "refresh_token is required")
+	}
+
+	client, err := authenticateClient(sctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := auth.FindRefreshToken(sctx, "", refreshPlain)
+	if errors.Is(err, auth.ErrRefreshTokenNotFound) || errors.Is(err, auth.ErrRefreshTokenInvalid) {
+		return nil, newOAuthError(errInvalidGrant, "invalid refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// токены /auth и других клиентов этим клиентом не обновляются (RFC 6749, 10.4)
+	if ref.ClientID != client.ID {
+		return nil, newOAuthError(errInvalidGrant, "refresh token was issued to another client")
+	}
+
+	scope, ok := narrowScope(ref.Scope, r.PostForm.Get("scope"))
+	if !ok {
+		return nil, newOAuthError(errInvalidScope, "requested scope exceeds the original grant")
+	}
+
+	pair, err := auth.RotateRefreshToken(sctx, r, ref, scope)
+	switch {
+	case errors.Is(err, auth.ErrRefreshTokenRevoked), errors.Is(err, auth.ErrRefreshTokenReused),
+		errors.Is(err, sessions.ErrRefreshTokenExpired), errors.Is(err, sessions.ErrSessionIdle), errors.Is(err, sessions.ErrSessionTooOld):
+		return nil, newOAuthError(errInvalidGrant, err.Error())
+	case errors.Is(err, helpers.ErrIPBindingViolation):
+		return nil, newOAuthError(errInvalidGrant, "refresh token is bound to another IP")
+	case err != nil:
+		return nil, err
+	}
+
+	return &tokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    pair.ExpiresIn,
+		RefreshToken: pair.RefreshToken,
+		Scope:        pair.Scope,
+
+		RefreshExpiresIn: pair.RefreshExpiresIn,
+	}, nil
+}
+
+// narrowScope — scope при обновлении можно только сузить (RFC 6749, 6). Пустой запрос — прежний scope
+func narrowScope(granted, requested string) (string, bool) {
+	scopes := strings.Fields(requested)
+	if len(scopes) == 0 {
+		return granted, true
+	}
+	for _, scope := range scopes {
+		if !hasScope(granted, scope) {
+			return "", false
+		}
+	}
+	return strings.Join(scopes, " "), true
+}
+
+// authenticateClient определяет клиента по HTTP Basic или client_id/client_secret из формы (RFC 6749, 2.3.1).
+// Публичные клиенты (SPA, мобильные) передают только client_id, конфиденциальные обязаны предъявить секрет
+func authenticateClient(sctx smart_context.ISmartContext, r *http.Request) (*model.Client, error) {
+	clientId, secret, ok := r.BasicAuth()
+	if ok {
+		// в Basic id и секрет дополнительно закодированы как application/x-www-form-urlencoded
+		clientId, _ = url.QueryUnescape(clientId)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientId, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	client, err := FindClient(sctx, clientId)
+	if errors.Is(err, ErrClientNotFound) {
+		return nil, newOAuthError(errInvalidClient, "unknown client")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !verifyClientSecret(sctx, client, secret) {
+		sctx.Warnf("Client authentication failed for client %s", clientId)
+		return nil, newOAuthError(errInvalidClient, "client authentication failed")
+	}
+	return client, nil
+}
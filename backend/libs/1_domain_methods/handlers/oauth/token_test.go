@@ -0,0 +1,27 @@
+package oauth
+
+import "testing"
+
+func TestNarrowScope(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested string
+		want      string
+		wantOK    bool
+	}{
+		{"empty keeps granted", "", "openid email orders:read", true},
+		{"subset", "orders:read openid", "orders:read openid", true},
+		{"same", "openid email orders:read", "openid email orders:read", true},
+		{"wider", "openid orders:write", "", false},
+		{"prefix is not a scope", "orders", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := narrowScope("openid email orders:read", tt.requested)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("narrowScope(%q) = %q, %v, want %q, %v", tt.requested, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"net/http"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	chi_middleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Audit записывает событие безопасности, дополняя его IP, user agent и request id из запроса
+func Audit(sctx smart_context.ISmartContext, r *http.Request, event types.AuditEvent) {
+	auditor := sctx.GetAuditor()
+	if auditor == nil {
+		return
+	}
+
+	if event.IPAddress == "" {
+		event.IPAddress = GetClientIP(r)
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = r.UserAgent()
+	}
+	if event.RequestID == "" {
+		event.RequestID = chi_middleware.GetReqID(r.Context())
+	}
+
+	auditor.Record(r.Context(), event)
+}
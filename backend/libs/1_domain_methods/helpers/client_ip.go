@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver определяет IP клиента. Заголовкам Forwarded, X-Forwarded-For и X-Real-IP
+// верим только если запрос пришёл от доверенного прокси
+type ClientIPResolver struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewClientIPResolver принимает список CIDR или отдельных IP доверенных прокси
+func NewClientIPResolver(trustedProxies []string) (*ClientIPResolver, error) {
+	resolver := &ClientIPResolver{}
+	for _, value := range trustedProxies {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+			}
+			value = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()).String()
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		resolver.trustedProxies = append(resolver.trustedProxies, prefix.Masked())
+	}
+	return resolver, nil
+}
+
+func (cr *ClientIPResolver) Resolve(r *http.Request) string {
+	remote, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !cr.isTrusted(remote) {
+		return remote.String()
+	}
+
+	// Цепочка прокси идёт слева направо, поэтому идём справа налево и берём первый недоверенный адрес.
+	// Всё, что левее, мог подставить сам клиент
+	chain := forwardedChain(r)
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseIP(chain[i])
+		if !ok {
+			// мусор в цепочке — дальше неё доверять нельзя
+			return remote.String()
+		}
+		if !cr.isTrusted(addr) {
+			return addr.String()
+		}
+		remote = addr
+	}
+
+	if len(chain) == 0 {
+		if addr, ok := parseIP(r.Header.Get("X-Real-IP")); ok {
+			return addr.String()
+		}
+	}
+
+	return remote.String()
+}
+
+func (cr *ClientIPResolver) isTrusted(addr netip.Addr) bool {
+	for _, prefix := range cr.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedChain возвращает цепочку адресов из Forwarded (RFC 7239), а если его нет — из X-Forwarded-For
+func forwardedChain(r *http.Request) []string {
+	var chain []string
+
+	for _, header := range r.Header.Values("Forwarded") {
+		for _, element := range strings.Split(header, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(key, "for") {
+					chain = append(chain, strings.Trim(value, `"`))
+				}
+			}
+		}
+	}
+	if len(chain) > 0 {
+		return chain
+	}
+
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, part := range strings.Split(header, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				chain = append(chain, part)
+			}
+		}
+	}
+	return chain
+}
+
+// parseIP разбирает IP с портом или без: "1.2.3.4", "1.2.3.4:80", "::1", "[::1]:80", "[fe80::1%eth0]"
+func parseIP(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return netip.Addr{}, false
+	}
+
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.WithZone("").Unmap(), true
+}
+
+var clientIPResolver = &ClientIPResolver{}
+
+// ConfigureTrustedProxies задаёт доверенные прокси для GetClientIP. По умолчанию доверенных прокси нет
+// и заголовки с адресом клиента игнорируются
+func ConfigureTrustedProxies(trustedProxies []string) error {
+	resolver, err := NewClientIPResolver(trustedProxies)
+	if err != nil {
+		return err
+	}
+	clientIPResolver = resolver
+	return nil
+}
+
+func GetClientIP(r *http.Request) string {
+	return clientIPResolver.Resolve(r)
+}
@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	trusted := []string{"10.0.0.0/8", "2001:db8:ffff::/48", "192.168.1.1"}
+
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "no proxy ipv4",
+			remoteAddr: "203.0.113.7:51234",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "no proxy ipv6",
+			remoteAddr: "[2001:db8::1]:51234",
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "ipv6 with zone",
+			remoteAddr: "[fe80::1%eth0]:51234",
+			want:       "fe80::1",
+		},
+		{
+			name:       "ipv4-mapped ipv6",
+			remoteAddr: "[::ffff:203.0.113.7]:51234",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "remote addr without port",
+			remoteAddr: "203.0.113.7",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "xff ignored from untrusted peer",
+			trusted:    trusted,
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "xff ignored without trusted proxies",
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "xff from trusted proxy",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "xff spoofed leftmost entry is skipped",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 10.0.0.9"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "xff all trusted returns leftmost",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Forwarded-For": "10.1.1.1, 10.0.0.9"},
+			want:       "10.1.1.1",
+		},
+		{
+			name:       "xff garbage stops walk",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1, not-an-ip"},
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "xff ipv6 entries",
+			trusted:    trusted,
+			remoteAddr: "[2001:db8:ffff::2]:443",
+			headers:    map[string]string{"X-Forwarded-For": "2001:db8:1::5, 2001:db8:ffff::3"},
+			want:       "2001:db8:1::5",
+		},
+		{
+			name:       "single trusted ip without mask",
+			trusted:    trusted,
+			remoteAddr: "192.168.1.1:8080",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "forwarded header",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"Forwarded": `for=1.1.1.1, for=198.51.100.1;proto=https, for=10.0.0.9`},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "forwarded quoted ipv6 with port",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"Forwarded": `For="[2001:db8:cafe::17]:4711"`},
+			want:       "2001:db8:cafe::17",
+		},
+		{
+			name:       "forwarded takes precedence over xff",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers: map[string]string{
+				"Forwarded":       "for=198.51.100.1",
+				"X-Forwarded-For": "198.51.100.2",
+			},
+			want: "198.51.100.1",
+		},
+		{
+			name:       "forwarded obfuscated identifier",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"Forwarded": "for=_hidden"},
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "x-real-ip from trusted proxy",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "x-real-ip ignored from untrusted peer",
+			trusted:    trusted,
+			remoteAddr: "203.0.113.7:51234",
+			headers:    map[string]string{"X-Real-IP": "198.51.100.1"},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "x-real-ip invalid",
+			trusted:    trusted,
+			remoteAddr: "10.0.0.5:51234",
+			headers:    map[string]string{"X-Real-IP": "garbage"},
+			want:       "10.0.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver, err := NewClientIPResolver(tt.trusted)
+			if err != nil {
+				t.Fatalf("NewClientIPResolver: %v", err)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, "/auth", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			if got := resolver.Resolve(r); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewClientIPResolverInvalid(t *testing.T) {
+	tests := []string{"10.0.0.0/33", "not-a-cidr", "300.1.1.1"}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			if _, err := NewClientIPResolver([]string{value}); err == nil {
+				t.Errorf("NewClientIPResolver(%q) expected error", value)
+			}
+		})
+	}
+}
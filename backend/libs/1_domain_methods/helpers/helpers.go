@@ -2,25 +2,32 @@ package helpers
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
-	"errors"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
-	"strings"
-	"test-task3/libs/4_common/smart_context"
-	"time"
-
-	"github.com/golang-jwt/jwt"
 )
 
-func GenerateJWT(sctx smart_context.ISmartContext, userId, ip string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS512)
-	claims := token.Claims.(jwt.MapClaims)
-
-	claims["user_id"] = userId
-	claims["ip"] = ip
-	claims["exp"] = time.Now().Add(15 * time.Minute).Unix() // 15 минут
+func GenerateRandomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
 
-	return token.SignedString([]byte(sctx.GetDbManager().GetJwtSecret()))
+// NewUUID генерирует случайный UUID версии 4
+func NewUUID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	buf[6] = (buf[6] & 0x0f) | 0x40
+	buf[8] = (buf[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16]), nil
 }
 
 func GenerateRandomBase64(n int) (string, error) {
@@ -32,34 +39,21 @@ func GenerateRandomBase64(n int) (string, error) {
 	return base64.StdEncoding.EncodeToString(buf), nil
 }
 
-func GetClientIP(r *http.Request) string {
-	xff := r.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		parts := strings.Split(xff, ",")
-		return strings.TrimSpace(parts[0])
-	}
-	return strings.Split(r.RemoteAddr, ":")[0]
+// HashToken — SHA-256 от случайного токена с высокой энтропией: bcrypt для таких не нужен, а по хэшу можно искать
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
-func ParseJWT(sctx smart_context.ISmartContext, tokenString string) (string, error) {
-	secret := sctx.GetDbManager().GetJwtSecret()
-	parsed, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		if t.Method != jwt.SigningMethodHS512 {
-			return nil, errors.New("unexpected signing method (want HS512)")
-		}
-		return []byte(secret), nil
-	})
-	if err != nil || !parsed.Valid {
-		return "", errors.New("invalid token")
-	}
-	claims, ok := parsed.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", errors.New("invalid claims")
+// APIKeyFromRequest — ключ из заголовка X-Api-Key или Apikey (оба разрешены в CORS)
+func APIKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-Api-Key"); key != "" {
+		return key
 	}
+	return r.Header.Get("Apikey")
+}
 
-	userId, _ := claims["user_id"].(string)
-	return userId, nil
+func WriteJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
 }
@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+	"test-task3/libs/4_common/metrics"
+	"test-task3/libs/4_common/smart_context"
+)
+
+type IPBindingPolicy string
+
+const (
+	// IPBindingIgnore — IP из токена не проверяется
+	IPBindingIgnore IPBindingPolicy = "ignore"
+	// IPBindingWarn — смена IP только логируется
+	IPBindingWarn IPBindingPolicy = "warn"
+	// IPBindingSubnet — IP должен быть из той же /24 (IPv4) или /64 (IPv6) подсети
+	IPBindingSubnet IPBindingPolicy = "subnet"
+	// IPBindingStrict — IP должен совпадать полностью
+	IPBindingStrict IPBindingPolicy = "strict"
+)
+
+const (
+	IPBindingScopeAccessToken  = "access_token"
+	IPBindingScopeRefreshToken = "refresh_token"
+)
+
+var ErrIPBindingViolation = errors.New("client IP does not match token IP")
+
+var ipBindingPolicy = IPBindingWarn
+
+func ParseIPBindingPolicy(value string) (IPBindingPolicy, error) {
+	switch policy := IPBindingPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return IPBindingWarn, nil
+	case IPBindingIgnore, IPBindingWarn, IPBindingSubnet, IPBindingStrict:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown IP binding policy %q", value)
+	}
+}
+
+// ConfigureIPBinding задаёт политику привязки к IP для всего процесса
+func ConfigureIPBinding(policy IPBindingPolicy) {
+	ipBindingPolicy = policy
+}
+
+func GetIPBindingPolicy() IPBindingPolicy {
+	return ipBindingPolicy
+}
+
+// CheckIPBinding сравнивает IP, к которому привязан токен, с текущим IP клиента по политике процесса.
+// Возвращает ErrIPBindingViolation, если политика запрещает такой запрос
+func CheckIPBinding(sctx smart_context.ISmartContext, scope, boundIP, clientIP string) error {
+	policy := ipBindingPolicy
+	decision, err := decideIPBinding(policy, boundIP, clientIP)
+
+	metrics.IPBindingDecisions.Add(scope+"."+string(policy)+"."+decision, 1)
+
+	switch {
+	case err != nil:
+		sctx.Warnf("IP binding violation (%s, policy=%s): token IP=%s, client IP=%s", scope, policy, boundIP, clientIP)
+	case decision == "mismatch_allowed" || decision == "subnet_match":
+		sctx.Warnf("IP changed (%s, policy=%s, decision=%s): token IP=%s, client IP=%s", scope, policy, decision, boundIP, clientIP)
+	default:
+		sctx.Debugf("IP binding (%s, policy=%s): %s", scope, policy, decision)
+	}
+
+	return err
+}
+
+func decideIPBinding(policy IPBindingPolicy, boundIP, clientIP string) (string, error) {
+	if policy == IPBindingIgnore {
+		return "ignored", nil
+	}
+	if boundIP == clientIP {
+		return "match", nil
+	}
+	// токены сервисов (client_credentials) к IP не привязываются
+	if boundIP == "" {
+		return "unbound", nil
+	}
+
+	switch policy {
+	case IPBindingSubnet:
+		if sameSubnet(boundIP, clientIP) {
+			return "subnet_match", nil
+		}
+		return "rejected", ErrIPBindingViolation
+	case IPBindingStrict:
+		return "rejected", ErrIPBindingViolation
+	default:
+		return "mismatch_allowed", nil
+	}
+}
+
+func sameSubnet(a, b string) bool {
+	addrA, okA := parseIP(a)
+	addrB, okB := parseIP(b)
+	if !okA || !okB || addrA.Is4() != addrB.Is4() {
+		return false
+	}
+
+	bits := 64
+	if addrA.Is4() {
+		bits = 24
+	}
+
+	prefix := netip.PrefixFrom(addrA, bits).Masked()
+	return prefix.Contains(addrB)
+}
@@ -0,0 +1,63 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecideIPBinding(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   IPBindingPolicy
+		boundIP  string
+		clientIP string
+		want     string
+		wantErr  bool
+	}{
+		{"ignore mismatch", IPBindingIgnore, "203.0.113.7", "198.51.100.1", "ignored", false},
+		{"warn match", IPBindingWarn, "203.0.113.7", "203.0.113.7", "match", false},
+		{"warn mismatch", IPBindingWarn, "203.0.113.7", "198.51.100.1", "mismatch_allowed", false},
+		{"unbound service token", IPBindingStrict, "", "198.51.100.1", "unbound", false},
+		{"subnet same /24", IPBindingSubnet, "203.0.113.7", "203.0.113.200", "subnet_match", false},
+		{"subnet other /24", IPBindingSubnet, "203.0.113.7", "203.0.114.7", "rejected", true},
+		{"subnet same /64", IPBindingSubnet, "2001:db8:1:2::1", "2001:db8:1:2:ffff::9", "subnet_match", false},
+		{"subnet other /64", IPBindingSubnet, "2001:db8:1:2::1", "2001:db8:1:3::1", "rejected", true},
+		{"strict match", IPBindingStrict, "203.0.113.7", "203.0.113.7", "match", false},
+		{"strict same subnet", IPBindingStrict, "203.0.113.7", "203.0.113.8", "rejected", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decideIPBinding(tt.policy, tt.boundIP, tt.clientIP)
+			if got != tt.want {
+				t.Errorf("decision = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr != errors.Is(err, ErrIPBindingViolation) {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSameSubnet(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"192.0.2.1", "192.0.2.254", true},
+		{"192.0.2.1", "192.0.3.1", false},
+		{"2001:db8::1", "2001:db8::ffff:1", true},
+		{"2001:db8:0:1::1", "2001:db8:0:2::1", false},
+		// IPv4-mapped IPv6 сравнивается как IPv4
+		{"::ffff:192.0.2.1", "192.0.2.7", true},
+		{"192.0.2.1", "2001:db8::1", false},
+		{"192.0.2.1", "not-an-ip", false},
+		{"", "192.0.2.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := sameSubnet(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameSubnet(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
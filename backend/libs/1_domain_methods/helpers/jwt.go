@@ -0,0 +1,37 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+)
+
+var ErrTokenRevoked = errors.New("token is revoked")
+
+// ParseJWT проверяет access-токен, отзыв и привязку к IP клиента запроса r; отзыв проверяется в контексте запроса.
+// Для истёкшего токена с верной подписью возвращаются и claims, и types.ErrTokenExpired —
+// вызывающий сам решает, допустимо ли это (например, при /auth/refresh). Привязка к IP проверяется
+// и у истёкшего токена: иначе /auth/refresh с чужого адреса обходил бы её
+func ParseJWT(sctx smart_context.ISmartContext, r *http.Request, tokenString string) (*types.AccessClaims, error) {
+	tokenService := sctx.GetTokenService()
+
+	claims, err := tokenService.ParseAccessToken(tokenString)
+	if err != nil && !errors.Is(err, types.ErrTokenExpired) {
+		return nil, err
+	}
+
+	revoked, revokedErr := tokenService.IsRevoked(r.Context(), claims)
+	if revokedErr != nil {
+		return nil, revokedErr
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	if bindingErr := CheckIPBinding(sctx, IPBindingScopeAccessToken, claims.IP, GetClientIP(r)); bindingErr != nil {
+		return nil, bindingErr
+	}
+
+	return claims, err
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"testing"
+)
+
+// fakeTokenService отдаёт заранее заданные claims и ошибку разбора
+type fakeTokenService struct {
+	smart_context.ITokenService
+	claims  *types.AccessClaims
+	err     error
+	revoked bool
+	ctx     context.Context
+}
+
+func (s *fakeTokenService) ParseAccessToken(string) (*types.AccessClaims, error) {
+	return s.claims, s.err
+}
+
+func (s *fakeTokenService) IsRevoked(ctx context.Context, _ *types.AccessClaims) (bool, error) {
+	s.ctx = ctx
+	return s.revoked, nil
+}
+
+type requestKey struct{}
+
+func TestParseJWT(t *testing.T) {
+	defer ConfigureIPBinding(GetIPBindingPolicy())
+	ConfigureIPBinding(IPBindingStrict)
+
+	claims := &types.AccessClaims{Subject: "user-1", IP: "203.0.113.7"}
+
+	tests := []struct {
+		name       string
+		service    *fakeTokenService
+		clientIP   string
+		wantErr    error
+		wantClaims bool
+	}{
+		{"valid", &fakeTokenService{claims: claims}, "203.0.113.7", nil, true},
+		{"invalid signature", &fakeTokenService{err: types.ErrTokenSignature}, "203.0.113.7", types.ErrTokenSignature, false},
+		{"revoked", &fakeTokenService{claims: claims, revoked: true}, "203.0.113.7", ErrTokenRevoked, false},
+		{"ip mismatch", &fakeTokenService{claims: claims}, "198.51.100.1", ErrIPBindingViolation, false},
+		{"expired", &fakeTokenService{claims: claims, err: types.ErrTokenExpired}, "203.0.113.7", types.ErrTokenExpired, true},
+		// истёкший токен с чужого адреса не должен пройти к /auth/refresh
+		{"expired ip mismatch", &fakeTokenService{claims: claims, err: types.ErrTokenExpired}, "198.51.100.1", ErrIPBindingViolation, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sctx := smart_context.NewSmartContext().WithTokenService(tt.service)
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), requestKey{}, tt.name))
+			r.RemoteAddr = tt.clientIP + ":40000"
+
+			got, err := ParseJWT(sctx, r, "token")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.service.ctx != nil && tt.service.ctx != r.Context() {
+				t.Error("IsRevoked() called without the request context")
+			}
+			if (got != nil) != tt.wantClaims {
+				t.Errorf("claims = %v, want claims %v", got, tt.wantClaims)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"errors"
+	"net/http"
+	"test-task3/libs/4_common/types"
+)
+
+// WriteTokenError отвечает 401 с причиной отказа, в том числе в WWW-Authenticate (RFC 6750)
+func WriteTokenError(w http.ResponseWriter, err error) {
+	description := "invalid access token"
+	switch {
+	case errors.Is(err, types.ErrTokenExpired):
+		description = "access token expired"
+	case errors.Is(err, types.ErrTokenNotYetValid):
+		description = "access token not yet valid"
+	case errors.Is(err, types.ErrTokenSignature):
+		description = "access token signature is invalid"
+	case errors.Is(err, types.ErrTokenAudience):
+		description = "access token has wrong audience"
+	case errors.Is(err, types.ErrTokenIssuer):
+		description = "access token has wrong issuer"
+	case errors.Is(err, types.ErrTokenMalformed), errors.Is(err, types.ErrTokenMissingClaims):
+		description = "access token is malformed"
+	case errors.Is(err, ErrTokenRevoked):
+		description = "access token is revoked"
+	case errors.Is(err, ErrIPBindingViolation):
+		description = "access token is bound to another IP"
+	}
+
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="`+description+`"`)
+	http.Error(w, description, http.StatusUnauthorized)
+}
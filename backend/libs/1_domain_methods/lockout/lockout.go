@@ -0,0 +1,195 @@
+package lockout
+
+import (
+	"errors"
+	"fmt"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	SubjectUser = "user"
+	SubjectIP   = "ip"
+)
+
+type LockedError struct {
+	SubjectType string
+	Subject     string
+	Until       time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s locked until %s", e.SubjectType, e.Until.Format(time.RFC3339))
+}
+
+type Config struct {
+	UserThreshold int
+	IPThreshold   int
+	BaseDuration  time.Duration
+	MaxDuration   time.Duration
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	return Config{
+		UserThreshold: env_vars.GetEnvAsInt(sctx, "LOCKOUT_USER_THRESHOLD", 5),
+		IPThreshold:   env_vars.GetEnvAsInt(sctx, "LOCKOUT_IP_THRESHOLD", 20),
+		BaseDuration:  time.Duration(env_vars.GetEnvAsInt(sctx, "LOCKOUT_BASE_SECONDS", 60)) * time.Second,
+		MaxDuration:   time.Duration(env_vars.GetEnvAsInt(sctx, "LOCKOUT_MAX_SECONDS", 3600)) * time.Second,
+	}
+}
+
+// Check возвращает *LockedError, если заблокирован аккаунт или IP. userId может быть пустым
+func Check(sctx smart_context.ISmartContext, userId, ip string) error {
+	now := time.Now().UTC()
+
+	var locks []model.LoginLockout
+	err := sctx.GetDB().
+		Where("((subject_type = ? AND subject = ?) OR (subject_type = ? AND subject = ?)) AND locked_until > ?",
+			SubjectUser, userId, SubjectIP, ip, now).
+		Order("locked_until DESC").
+		Find(&locks).Error
+	if err != nil {
+		return err
+	}
+	if len(locks) == 0 {
+		return nil
+	}
+
+	return &LockedError{SubjectType: locks[0].SubjectType, Subject: locks[0].Subject, Until: locks[0].LockedUntil}
+}
+
+// RegisterFailure увеличивает счётчики неудачных попыток аккаунта и IP и возвращает блокировки,
+// которые были установлены этой попыткой. Каждая следующая блокировка вдвое дольше предыдущей
+func RegisterFailure(sctx smart_context.ISmartContext, cfg Config, userId, ip string) ([]*LockedError, error) {
+	var locks []*LockedError
+
+	if userId != "" {
+		lock, err := registerFailure(sctx, cfg, SubjectUser, userId, cfg.UserThreshold)
+		if err != nil {
+			return nil, err
+		}
+		if lock != nil {
+			locks = append(locks, lock)
+		}
+	}
+
+	lock, err := registerFailure(sctx, cfg, SubjectIP, ip, cfg.IPThreshold)
+	if err != nil {
+		return locks, err
+	}
+	if lock != nil {
+		locks = append(locks, lock)
+	}
+
+	return locks, nil
+}
+
+// RegisterSuccess сбрасывает счётчики аккаунта после успешного входа. Счётчик IP не сбрасывается,
+// иначе атакующий мог бы обнулять его, периодически входя в свой собственный аккаунт
+func RegisterSuccess(sctx smart_context.ISmartContext, userId string) error {
+	return sctx.GetDB().Model(&model.LoginLockout{}).
+		Where("subject_type = ? AND subject = ?", SubjectUser, userId).
+		Updates(map[string]interface{}{
+			"failed_attempts": 0,
+			"lockout_count":   0,
+			"updated_at":      time.Now().UTC(),
+		}).Error
+}
+
+// Unlock снимает блокировку и сбрасывает счётчики. Возвращает false, если subject не был заблокирован
+func Unlock(sctx smart_context.ISmartContext, subjectType, subject string) (bool, error) {
+	now := time.Now().UTC()
+	result := sctx.GetDB().Model(&model.LoginLockout{}).
+		Where("subject_type = ? AND subject = ? AND locked_until > ?", subjectType, subject, now).
+		Updates(map[string]interface{}{
+			"failed_attempts": 0,
+			"lockout_count":   0,
+			"locked_until":    now,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListActive возвращает все действующие блокировки
+func ListActive(sctx smart_context.ISmartContext) ([]model.LoginLockout, error) {
+	var locks []model.LoginLockout
+	err := sctx.GetDB().
+		Where("locked_until > ?", time.Now().UTC()).
+		Order("locked_until DESC").
+		Find(&locks).Error
+	return locks, err
+}
+
+func registerFailure(sctx smart_context.ISmartContext, cfg Config, subjectType, subject string, threshold int) (*LockedError, error) {
+	if threshold <= 0 {
+		return nil, nil
+	}
+
+	var locked *LockedError
+	err := sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+
+		lock := model.LoginLockout{
+			SubjectType: subjectType,
+			Subject:     subject,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("subject_type = ? AND subject = ?", subjectType, subject).
+			First(&lock).Error
+		if err != nil {
+			return err
+		}
+		if lock.LockedUntil.After(now) {
+			// уже заблокирован — попытки во время блокировки не продлевают её
+			return nil
+		}
+
+		lock.FailedAttempts++
+		lock.LastFailedAt = now
+		lock.UpdatedAt = now
+
+		if int(lock.FailedAttempts) >= threshold {
+			lock.LockoutCount++
+			lock.FailedAttempts = 0
+			lock.LockedUntil = now.Add(lockoutDuration(cfg, int(lock.LockoutCount)))
+			sctx.Warnf("Too many failed logins: %s %s locked until %s (lockout #%d)",
+				subjectType, subject, lock.LockedUntil.Format(time.RFC3339), lock.LockoutCount)
+			locked = &LockedError{SubjectType: subjectType, Subject: subject, Until: lock.LockedUntil}
+		}
+
+		return tx.Save(&lock).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return locked, nil
+}
+
+func lockoutDuration(cfg Config, lockoutCount int) time.Duration {
+	duration := cfg.BaseDuration
+	for i := 1; i < lockoutCount && duration < cfg.MaxDuration; i++ {
+		duration *= 2
+	}
+	return min(duration, cfg.MaxDuration)
+}
+
+func IsLocked(err error) (*LockedError, bool) {
+	var lockedErr *LockedError
+	if errors.As(err, &lockedErr) {
+		return lockedErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,212 @@
+package magic_link
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLinkInvalid    = errors.New("invalid or expired magic link")
+	ErrDeviceMismatch = errors.New("magic link was requested from another device")
+)
+
+type Config struct {
+	TTL time.Duration
+	// LinkURL — страница подтверждения входа, токен добавляется параметром token
+	LinkURL string
+	// RequireDevice — все ссылки привязываются к устройству, даже если клиент об этом не просил
+	RequireDevice bool
+
+	signingKey []byte
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	cfg := Config{
+		TTL:           time.Duration(env_vars.GetEnvAsInt(sctx, "MAGIC_LINK_TTL_SECONDS", 600)) * time.Second,
+		LinkURL:       os.Getenv("MAGIC_LINK_URL"),
+		RequireDevice: os.Getenv("MAGIC_LINK_REQUIRE_DEVICE") == "true",
+	}
+	if cfg.LinkURL == "" {
+		cfg.LinkURL = "http://localhost:4000/auth/magic-link/verify"
+	}
+	if dbm := sctx.GetDbManager(); dbm != nil {
+		cfg.signingKey = deriveSigningKey(dbm.GetJwtSecret())
+	}
+	return cfg
+}
+
+// Send отправляет ссылку для входа. deviceToken — секрет устройства, запросившего ссылку: без него
+// ссылка не сработает; пустой — ссылка не привязана. Для неизвестного email ничего не делает
+// и возвращает пустой userId — ответ клиенту от этого не зависит
+func Send(sctx smart_context.ISmartContext, cfg Config, email, clientIP, deviceToken string) (string, error) {
+	var user model.User
+	err := sctx.GetDB().Where("lower(email) = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return user.ID, err
+	}
+	expiresAt := time.Now().Add(cfg.TTL)
+
+	link := model.MagicLink{
+		TokenHash: helpers.HashToken(secret),
+		UserID:    user.ID,
+		IPAddress: clientIP,
+		ExpiresAt: expiresAt,
+	}
+	if deviceToken != "" {
+		link.DeviceHash = helpers.HashToken(deviceToken)
+	}
+	if err := sctx.GetDB().Omit("UsedAt").Create(&link).Error; err != nil {
+		return user.ID, err
+	}
+
+	mailer := sctx.GetMailer()
+	if mailer == nil {
+		return user.ID, errors.New("mailer is not configured")
+	}
+	return user.ID, mailer.Send(sctx.GetContext(), linkMessage(cfg, user.Email, signToken(cfg.signingKey, secret, expiresAt)))
+}
+
+// CheckFunc решает, пускать ли владельца ссылки. Вызывается до того, как ссылка гасится
+type CheckFunc func(userId string) error
+
+// Consume проверяет подпись и срок ссылки, привязку к устройству, вызывает check и гасит ссылку.
+// Ошибка check возвращается как есть, а ссылка остаётся действительной. Вход по ссылке
+// подтверждает владение email, поэтому неподтверждённый адрес отмечается подтверждённым.
+// userId заполнен и при ошибке, если ссылка найдена, — чтобы записать неудачу в аудит
+func Consume(sctx smart_context.ISmartContext, cfg Config, token, deviceToken string, check CheckFunc) (string, error) {
+	secret, err := verifyToken(cfg.signingKey, token, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	var link model.MagicLink
+	err = sctx.GetDB().Where("token_hash = ?", helpers.HashToken(secret)).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrLinkInvalid
+	}
+	if err != nil {
+		return "", err
+	}
+	if !link.UsedAt.IsZero() || time.Now().After(link.ExpiresAt) {
+		return link.UserID, ErrLinkInvalid
+	}
+	// ссылка при этом не гасится: её ещё можно открыть на нужном устройстве
+	if link.DeviceHash != "" && subtle.ConstantTimeCompare([]byte(link.DeviceHash), []byte(helpers.HashToken(deviceToken))) != 1 {
+		return link.UserID, ErrDeviceMismatch
+	}
+	if err := check(link.UserID); err != nil {
+		return link.UserID, err
+	}
+
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.MagicLink{}).
+			Where("id = ? AND used_at IS NULL", link.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrLinkInvalid
+		}
+
+		return tx.Model(&model.User{}).
+			Where("id = ? AND email_verified_at IS NULL", link.UserID).
+			Update("email_verified_at", now).Error
+	})
+	if err != nil {
+		return link.UserID, err
+	}
+
+	return link.UserID, nil
+}
+
+// Токен ссылки — "<secret>.<expires unix>.<подпись>": подпись HMAC-SHA256 отсекает подделанные
+// и просроченные ссылки без запроса к базе, а одноразовость обеспечивает строка в magic_links
+func signToken(key []byte, secret string, expiresAt time.Time) string {
+	payload := secret + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sign(key, payload))
+}
+
+func verifyToken(key []byte, token string, now time.Time) (string, error) {
+	payload, signature, ok := cutLast(token, ".")
+	if !ok {
+		return "", ErrLinkInvalid
+	}
+	secret, expires, ok := strings.Cut(payload, ".")
+	if !ok || secret == "" {
+		return "", ErrLinkInvalid
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, sign(key, payload)) {
+		return "", ErrLinkInvalid
+	}
+
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.After(time.Unix(expiresUnix, 0)) {
+		return "", ErrLinkInvalid
+	}
+	return secret, nil
+}
+
+func sign(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// deriveSigningKey — отдельный от подписи JWT ключ, выведенный из JWT_SECRET
+func deriveSigningKey(jwtSecret string) []byte {
+	return sign([]byte(jwtSecret), "magic-link")
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func linkMessage(cfg Config, email, token string) types.MailMessage {
+	link := token
+	if linkURL, err := url.Parse(cfg.LinkURL); err == nil {
+		query := linkURL.Query()
+		query.Set("token", token)
+		linkURL.RawQuery = query.Encode()
+		link = linkURL.String()
+	}
+
+	return types.MailMessage{
+		To:      email,
+		Subject: "Your sign-in link",
+		Text: fmt.Sprintf("Use this link to sign in within %d minutes. It works only once:\n%s\n\n"+
+			"If you didn't try to sign in, ignore this email.\n",
+			int(cfg.TTL.Minutes()), link),
+	}
+}
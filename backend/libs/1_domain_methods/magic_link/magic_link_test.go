@@ -0,0 +1,85 @@
+package magic_link
+
+import (
+	"errors"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/test_db"
+	"testing"
+	"time"
+)
+
+func TestVerifyToken(t *testing.T) {
+	key := deriveSigningKey("jwt-secret")
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	token := signToken(key, "abc123", now.Add(10*time.Minute))
+
+	secret, err := verifyToken(key, token, now)
+	if err != nil || secret != "abc123" {
+		t.Fatalf("verifyToken() = %q, %v, want abc123", secret, err)
+	}
+
+	payload, signature, _ := cutLast(token, ".")
+	_, expires, _ := strings.Cut(payload, ".")
+
+	cases := map[string]struct {
+		key   []byte
+		token string
+		now   time.Time
+	}{
+		"expired":            {key, token, now.Add(11 * time.Minute)},
+		"other key":          {deriveSigningKey("other-secret"), token, now},
+		"other secret":       {key, "abc124." + expires + "." + signature, now},
+		"extended expiry":    {key, "abc123.9999999999." + signature, now},
+		"missing signature":  {key, payload, now},
+		"malformed":          {key, "abc123", now},
+		"empty secret":       {key, signToken(key, "", now.Add(time.Minute)), now},
+		"signature encoding": {key, payload + ".!!!", now},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := verifyToken(tc.key, tc.token, tc.now); !errors.Is(err, ErrLinkInvalid) {
+				t.Errorf("verifyToken() error = %v, want ErrLinkInvalid", err)
+			}
+		})
+	}
+}
+
+func TestConsumeChecksBeforeUsingLink(t *testing.T) {
+	db := test_db.Open(t, "users", "magic_links")
+	if err := db.Create(&model.User{ID: "user-1", Email: "alice@example.com", Password: "hash"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	sctx := smart_context.NewSmartContext().WithDB(db)
+	cfg := Config{TTL: 10 * time.Minute, signingKey: deriveSigningKey("jwt-secret")}
+
+	expiresAt := time.Now().Add(cfg.TTL)
+	link := model.MagicLink{TokenHash: helpers.HashToken("abc123"), UserID: "user-1", ExpiresAt: expiresAt}
+	if err := db.Omit("UsedAt").Create(&link).Error; err != nil {
+		t.Fatal(err)
+	}
+	token := signToken(cfg.signingKey, "abc123", expiresAt)
+
+	// заблокированный пользователь не входит, но ссылка не сгорает
+	errLocked := errors.New("locked")
+	userId, err := Consume(sctx, cfg, token, "", func(string) error { return errLocked })
+	if !errors.Is(err, errLocked) || userId != "user-1" {
+		t.Fatalf("Consume() = %q, %v, want user-1, %v", userId, err, errLocked)
+	}
+
+	var checked string
+	userId, err = Consume(sctx, cfg, token, "", func(userId string) error {
+		checked = userId
+		return nil
+	})
+	if err != nil || userId != "user-1" || checked != "user-1" {
+		t.Fatalf("Consume() after unlock = %q, %v (checked %q)", userId, err, checked)
+	}
+
+	// ссылка одноразовая
+	if _, err := Consume(sctx, cfg, token, "", func(string) error { return nil }); !errors.Is(err, ErrLinkInvalid) {
+		t.Errorf("second Consume() error = %v, want %v", err, ErrLinkInvalid)
+	}
+}
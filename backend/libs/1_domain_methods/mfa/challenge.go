@@ -0,0 +1,76 @@
+package mfa
+
+import (
+	"errors"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreateChallenge выдаёт токен второго шага входа: первый фактор (amr — пароль или ссылка из письма)
+// уже проверен, осталось ввести код. Токен одноразовый, живёт cfg.ChallengeTTL и хранится только как хэш
+func CreateChallenge(sctx smart_context.ISmartContext, cfg Config, userId, clientIP string, amr []string) (string, error) {
+	token, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := model.MfaChallenge{
+		TokenHash: helpers.HashToken(token),
+		UserID:    userId,
+		IPAddress: clientIP,
+		ExpiresAt: time.Now().Add(cfg.ChallengeTTL),
+		Amr:       strings.Join(amr, " "),
+	}
+	if err := sctx.GetDB().Create(&challenge).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CompleteChallenge проверяет код для токена второго шага и гасит токен. userId заполнен и при
+// ErrInvalidCode, чтобы неудачу можно было записать на пользователя. После cfg.ChallengeAttempts
+// неверных кодов токен перестаёт приниматься, и вход начинается заново с первого фактора.
+// amr — способы входа обоих шагов
+func CompleteChallenge(sctx smart_context.ISmartContext, cfg Config, token, code string) (userId, method string, amr []string, err error) {
+	var challenge model.MfaChallenge
+	err = sctx.GetDB().Where("token_hash = ?", helpers.HashToken(token)).First(&challenge).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", "", nil, ErrChallengeInvalid
+	}
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if challenge.Used || time.Now().After(challenge.ExpiresAt) || int(challenge.Attempts) >= cfg.ChallengeAttempts {
+		return challenge.UserID, "", nil, ErrChallengeInvalid
+	}
+
+	method, err = Verify(sctx, challenge.UserID, code)
+	if errors.Is(err, ErrInvalidCode) {
+		if err := sctx.GetDB().Model(&challenge).Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+			sctx.Errorf("update mfa challenge attempts error: %v", err)
+		}
+		return challenge.UserID, "", nil, err
+	}
+	if err != nil {
+		return challenge.UserID, "", nil, err
+	}
+
+	result := sctx.GetDB().Model(&model.MfaChallenge{}).
+		Where("id = ? AND used = false", challenge.ID).
+		Update("used", true)
+	if result.Error != nil {
+		return challenge.UserID, "", nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return challenge.UserID, "", nil, ErrChallengeInvalid
+	}
+
+	return challenge.UserID, method, append(strings.Fields(challenge.Amr), types.AMROTP), nil
+}
@@ -0,0 +1,83 @@
+package mfa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var encryptionKey []byte
+
+// ConfigureEncryptionKey задаёт ключ AES-256-GCM для секретов TOTP. Ключ — 32 байта в hex или base64
+// (MFA_ENCRYPTION_KEY). Без него ключ выводится из fallbackSecret (JWT_SECRET), и смена JWT_SECRET
+// сделает сохранённые секреты нечитаемыми
+func ConfigureEncryptionKey(value, fallbackSecret string) (bool, error) {
+	if value == "" {
+		sum := sha256.Sum256([]byte("mfa-encryption:" + fallbackSecret))
+		encryptionKey = sum[:]
+		return false, nil
+	}
+
+	key, err := hex.DecodeString(value)
+	if err != nil {
+		key, err = base64.StdEncoding.DecodeString(value)
+	}
+	if err != nil || len(key) != 32 {
+		return false, errors.New("expected 32 bytes in hex or base64")
+	}
+
+	encryptionKey = key
+	return true, nil
+}
+
+func encryptSecret(plain []byte) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, plain, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(encrypted string) ([]byte, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("malformed encrypted secret")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt secret: %w", err)
+	}
+	return plain, nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	if encryptionKey == nil {
+		return nil, errors.New("mfa encryption key is not configured")
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
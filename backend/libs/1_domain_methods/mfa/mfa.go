@@ -0,0 +1,304 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Способ, которым пройден второй фактор, — для аудита
+const (
+	MethodTOTP         = "totp"
+	MethodRecoveryCode = "recovery_code"
+)
+
+const (
+	// 160 бит — длина секрета, рекомендованная RFC 4226
+	secretSize        = 20
+	recoveryCodeCount = 10
+	// recoveryCodeSize — 80 бит: хэш без соли из дампа БД перебором не обратить
+	recoveryCodeSize = 10
+)
+
+var (
+	ErrNotEnrolled      = errors.New("totp is not enrolled")
+	ErrAlreadyEnrolled  = errors.New("totp is already enabled")
+	ErrInvalidCode      = errors.New("invalid verification code")
+	ErrChallengeInvalid = errors.New("invalid or expired mfa challenge")
+)
+
+type Config struct {
+	// Issuer — название сервиса в приложении-аутентификаторе
+	Issuer            string
+	ChallengeTTL      time.Duration
+	ChallengeAttempts int
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	issuer := os.Getenv("MFA_TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "test-task3"
+	}
+
+	return Config{
+		Issuer:            issuer,
+		ChallengeTTL:      time.Duration(env_vars.GetEnvAsInt(sctx, "MFA_CHALLENGE_TTL_SECONDS", 300)) * time.Second,
+		ChallengeAttempts: env_vars.GetEnvAsInt(sctx, "MFA_CHALLENGE_MAX_ATTEMPTS", 5),
+	}
+}
+
+// Enrollment — секрет для ручного ввода и otpauth:// для QR-кода. Показывается один раз
+type Enrollment struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// Enroll создаёт новый неподтверждённый секрет вместо прежнего неподтверждённого.
+// Второй фактор включается только после Confirm
+func Enroll(sctx smart_context.ISmartContext, cfg Config, userId string) (*Enrollment, error) {
+	var user model.User
+	if err := sctx.GetDB().Where("id = ?", userId).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		existing, err := findTOTP(tx, userId)
+		switch {
+		case errors.Is(err, ErrNotEnrolled):
+		case err != nil:
+			return err
+		case !existing.ConfirmedAt.IsZero():
+			return ErrAlreadyEnrolled
+		default:
+			if err := tx.Delete(existing).Error; err != nil {
+				return err
+			}
+		}
+
+		row := model.UserTotp{UserID: userId, EncryptedSecret: encrypted}
+		return tx.Omit("ConfirmedAt").Create(&row).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Enrollment{
+		Secret:     base32NoPadding.EncodeToString(secret),
+		OtpauthURI: otpauthURI(cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm проверяет первый код из приложения, включает второй фактор и возвращает recovery-коды
+func Confirm(sctx smart_context.ISmartContext, userId, code string) ([]string, error) {
+	var codes []string
+	err := sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		totp, err := findTOTP(tx, userId)
+		if err != nil {
+			return err
+		}
+		if !totp.ConfirmedAt.IsZero() {
+			return ErrAlreadyEnrolled
+		}
+
+		ok, err := useTOTPCode(tx, totp, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidCode
+		}
+
+		if err := tx.Model(totp).Update("confirmed_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(tx, userId)
+		return err
+	})
+	return codes, err
+}
+
+// IsEnabled — у пользователя подтверждён TOTP и вход требует второго шага
+func IsEnabled(sctx smart_context.ISmartContext, userId string) (bool, error) {
+	totp, err := findTOTP(sctx.GetDB(), userId)
+	if errors.Is(err, ErrNotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !totp.ConfirmedAt.IsZero(), nil
+}
+
+// Verify принимает код из приложения или неиспользованный recovery-код и возвращает способ.
+// Принятый код повторно не сработает
+func Verify(sctx smart_context.ISmartContext, userId, code string) (string, error) {
+	totp, err := findTOTP(sctx.GetDB(), userId)
+	if err != nil {
+		return "", err
+	}
+	if totp.ConfirmedAt.IsZero() {
+		return "", ErrNotEnrolled
+	}
+
+	if isTOTPCode(code) {
+		ok, err := useTOTPCode(sctx.GetDB(), totp, code)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return MethodTOTP, nil
+		}
+		return "", ErrInvalidCode
+	}
+
+	result := sctx.GetDB().Model(&model.MfaRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", ErrInvalidCode
+	}
+	return MethodRecoveryCode, nil
+}
+
+// Disable выключает второй фактор после проверки кода. Возвращает способ, которым код подтверждён
+func Disable(sctx smart_context.ISmartContext, userId, code string) (string, error) {
+	method, err := Verify(sctx, userId, code)
+	if err != nil {
+		return "", err
+	}
+
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&model.MfaRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userId).Delete(&model.UserTotp{}).Error
+	})
+	return method, err
+}
+
+// RegenerateRecoveryCodes после проверки кода заменяет все recovery-коды новыми
+func RegenerateRecoveryCodes(sctx smart_context.ISmartContext, userId, code string) ([]string, error) {
+	if _, err := Verify(sctx, userId, code); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err := sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = replaceRecoveryCodes(tx, userId)
+		return err
+	})
+	return codes, err
+}
+
+func findTOTP(db *gorm.DB, userId string) (*model.UserTotp, error) {
+	var totp model.UserTotp
+	err := db.Where("user_id = ?", userId).First(&totp).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotEnrolled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &totp, nil
+}
+
+// useTOTPCode проверяет код и атомарно сдвигает last_used_step, так что параллельный запрос
+// с тем же кодом получит false
+func useTOTPCode(db *gorm.DB, totp *model.UserTotp, code string) (bool, error) {
+	secret, err := decryptSecret(totp.EncryptedSecret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := matchTOTP(secret, code, time.Now(), uint64(totp.LastUsedStep))
+	if !ok {
+		return false, nil
+	}
+
+	result := db.Model(&model.UserTotp{}).
+		Where("user_id = ? AND last_used_step < ?", totp.UserID, int64(step)).
+		Update("last_used_step", int64(step))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func replaceRecoveryCodes(db *gorm.DB, userId string) ([]string, error) {
+	if err := db.Where("user_id = ?", userId).Delete(&model.MfaRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	rows := make([]model.MfaRecoveryCode, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		codes = append(codes, code)
+		rows = append(rows, model.MfaRecoveryCode{UserID: userId, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := db.Omit("UsedAt").Create(&rows).Error; err != nil {
+		return nil, fmt.Errorf("save recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// newRecoveryCode — 20 hex-символов группами по 5: a1b2c-3d4e5-f6a7b-8c9d0
+func newRecoveryCode() (string, error) {
+	raw, err := helpers.GenerateRandomHex(recoveryCodeSize)
+	if err != nil {
+		return "", err
+	}
+
+	groups := make([]string, 0, len(raw)/5)
+	for i := 0; i < len(raw); i += 5 {
+		groups = append(groups, raw[i:i+5])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// hashRecoveryCode не зависит от регистра и разделителей, с которыми пользователь ввёл код
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	return helpers.HashToken(normalized)
+}
+
+func isTOTPCode(code string) bool {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package mfa
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewRecoveryCode(t *testing.T) {
+	format := regexp.MustCompile(`^[0-9a-f]{5}(-[0-9a-f]{5}){3}$`)
+
+	seen := map[string]bool{}
+	for range 20 {
+		code, err := newRecoveryCode()
+		if err != nil {
+			t.Fatalf("newRecoveryCode() error: %v", err)
+		}
+		if !format.MatchString(code) {
+			t.Fatalf("newRecoveryCode() = %q, want 4 groups of 5 hex characters", code)
+		}
+		if seen[code] {
+			t.Fatalf("newRecoveryCode() repeated %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestHashRecoveryCodeNormalizes(t *testing.T) {
+	want := hashRecoveryCode("a1b2c-3d4e5-f6a7b-8c9d0")
+	for _, input := range []string{"A1B2C-3D4E5-F6A7B-8C9D0", " a1b2c3d4e5f6a7b8c9d0 ", "a1b2c 3d4e5 f6a7b 8c9d0"} {
+		if got := hashRecoveryCode(input); got != want {
+			t.Errorf("hashRecoveryCode(%q) differs from the canonical form", input)
+		}
+	}
+	if hashRecoveryCode("a1b2c-3d4e5-f6a7b-8c9d1") == want {
+		t.Error("different codes have the same hash")
+	}
+}
@@ -0,0 +1,81 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// параметры TOTP по умолчанию из RFC 6238 — их понимают все приложения-аутентификаторы
+const (
+	totpDigits = 6
+	totpPeriod = 30 * time.Second
+	// totpSkew — сколько соседних шагов принимать из-за расхождения часов телефона
+	totpSkew = 1
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// totpCode считает код для шага counter (RFC 4226, 5.3)
+func totpCode(secret []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+func totpStep(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(totpPeriod.Seconds())
+}
+
+// matchTOTP ищет шаг, для которого код совпадает, в окне ±totpSkew. Шаги не новее afterStep
+// не принимаются, чтобы один и тот же код нельзя было использовать дважды
+func matchTOTP(secret []byte, code string, now time.Time, afterStep uint64) (uint64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := totpStep(now)
+	for delta := -totpSkew; delta <= totpSkew; delta++ {
+		step := uint64(int64(current) + int64(delta))
+		if step <= afterStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// otpauthURI — ссылка для QR-кода в формате Key Uri Format (Google Authenticator)
+func otpauthURI(issuer, account string, secret []byte) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+
+	query := url.Values{}
+	query.Set("secret", base32NoPadding.EncodeToString(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
@@ -0,0 +1,58 @@
+package mfa
+
+import (
+	"testing"
+	"time"
+)
+
+// Тестовые векторы RFC 6238, приложение B (SHA1), усечённые до 6 цифр
+func TestTOTPRFC6238Vectors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		if got := totpCode(secret, totpStep(time.Unix(tt.unix, 0))); got != tt.want {
+			t.Errorf("totpCode(T=%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	step := totpStep(now)
+
+	tests := []struct {
+		name      string
+		code      string
+		afterStep uint64
+		wantOK    bool
+	}{
+		{"current step", totpCode(secret, step), 0, true},
+		{"previous step within skew", totpCode(secret, step-1), 0, true},
+		{"next step within skew", totpCode(secret, step+1), 0, true},
+		{"outside skew", totpCode(secret, step-2), 0, false},
+		{"spaces are ignored", totpCode(secret, step)[:3] + " " + totpCode(secret, step)[3:], 0, true},
+		{"replay of used step", totpCode(secret, step), step, false},
+		{"wrong length", "12345", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := matchTOTP(secret, tt.code, now, tt.afterStep); ok != tt.wantOK {
+				t.Errorf("matchTOTP(%q) ok = %v, want %v", tt.code, ok, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+)
+
+// RequireAdmin пускает пользователей с ролью role (Authenticate + RequireRole).
+// Статический ключ ADMIN_API_KEY в заголовке X-Api-Key или Apikey остаётся аварийным доступом,
+// пока роль никому не назначена: после назначения первого админа ключ отклоняется. Без переменной этот путь выключен.
+// По умолчанию требуется вход со вторым фактором: TOTP или passkey (amr otp или mfa), ADMIN_REQUIRE_MFA=false это отключает.
+// На ключ ADMIN_REQUIRE_MFA не действует — второго фактора у него нет
+func RequireAdmin(sctx smart_context.ISmartContext, role string) func(http.Handler) http.Handler {
+	adminKey := os.Getenv("ADMIN_API_KEY")
+	requireMFA := os.Getenv("ADMIN_REQUIRE_MFA") != "false"
+	authenticate := Authenticate(sctx)
+	requireRole := RequireRole(role)
+	requireSecondFactor := RequireAMR(types.AMROTP, types.AMRMultiFactor)
+
+	return func(next http.Handler) http.Handler {
+		byRole := authenticate(requireRole(next))
+		if requireMFA {
+			byRole = authenticate(requireRole(requireSecondFactor(next)))
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := helpers.APIKeyFromRequest(r)
+			if adminKey != "" && key != "" && subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) == 1 {
+				assigned, err := access.RoleAssigned(sctx, role)
+				if err != nil {
+					sctx.Errorf("check role %s assigned error: %v", role, err)
+					http.Error(w, "internal error", http.StatusInternalServerError)
+					return
+				}
+				if !assigned {
+					sctx.Warnf("Admin API accessed with ADMIN_API_KEY: %s %s", r.Method, r.URL.Path)
+					next.ServeHTTP(w, r)
+					return
+				}
+				sctx.Warnf("ADMIN_API_KEY rejected: role %s is already assigned, %s %s", role, r.Method, r.URL.Path)
+			}
+
+			byRole.ServeHTTP(w, r)
+		})
+	}
+}
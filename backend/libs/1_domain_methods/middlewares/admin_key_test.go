@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"test-task3/libs/3_infrastructure/token_service"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"testing"
+)
+
+func TestRequireAdminSecondFactor(t *testing.T) {
+	tokens := token_service.NewJwtTokenService("test-secret", token_service.DefaultConfig(), nil)
+	sctx := smart_context.NewSmartContext().WithTokenService(tokens)
+
+	serve := func(amr ...string) int {
+		t.Helper()
+
+		token, _, err := tokens.IssueAccessToken(types.AccessClaims{UserID: "admin-1", IP: "203.0.113.7", Roles: []string{"admin"}, AMR: amr})
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler := RequireAdmin(sctx, "admin")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+		r.RemoteAddr = "203.0.113.7:40000"
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// второй фактор требуется без всякой настройки
+	if code := serve(types.AMRPassword); code != http.StatusForbidden {
+		t.Errorf("password only status = %d, want %d", code, http.StatusForbidden)
+	}
+	if code := serve(types.AMRPassword, types.AMROTP); code != http.StatusNoContent {
+		t.Errorf("password and otp status = %d, want %d", code, http.StatusNoContent)
+	}
+	if code := serve(types.AMRHardwareKey, types.AMRMultiFactor); code != http.StatusNoContent {
+		t.Errorf("passkey status = %d, want %d", code, http.StatusNoContent)
+	}
+
+	t.Setenv("ADMIN_REQUIRE_MFA", "false")
+	if code := serve(types.AMRPassword); code != http.StatusNoContent {
+		t.Errorf("password only with ADMIN_REQUIRE_MFA=false status = %d, want %d", code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"test-task3/libs/1_domain_methods/api_keys"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+)
+
+var errMissingBearerToken = errors.New("missing bearer token")
+
+// Authenticate требует access-токен в заголовке Authorization: Bearer или API-ключ в X-Api-Key/Apikey
+// и кладёт claims в ISmartContext запроса (smart_context.FromContext). Для RequireScope и RequireRole
+// ключ и токен равноценны
+func Authenticate(sctx smart_context.ISmartContext) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqCtx := RequestSmartContext(sctx, r)
+
+			if key := helpers.APIKeyFromRequest(r); key != "" && r.Header.Get("Authorization") == "" {
+				claims, err := api_keys.Authenticate(reqCtx, key)
+				if err != nil {
+					reqCtx.Debugf("api key rejected: %v", err)
+					helpers.Audit(reqCtx, r, types.AuditEvent{
+						EventType: types.AuditAccessDenied,
+						Outcome:   types.AuditFailure,
+						Details:   types.Fields{"reason": err.Error(), "method": "api_key"},
+					})
+					http.Error(w, "invalid api key", http.StatusUnauthorized)
+					return
+				}
+
+				reqCtx = reqCtx.WithAccessClaims(claims).LogField("user_id", claims.GetUserID())
+				next.ServeHTTP(w, r.WithContext(smart_context.ToContext(r.Context(), reqCtx)))
+				return
+			}
+
+			tokenString, err := bearerToken(r)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := helpers.ParseJWT(reqCtx, r, tokenString)
+			if err != nil {
+				reqCtx.Debugf("access token rejected: %v", err)
+				helpers.Audit(reqCtx, r, types.AuditEvent{
+					EventType: types.AuditAccessDenied,
+					Outcome:   types.AuditFailure,
+					Details:   types.Fields{"reason": err.Error()},
+				})
+				helpers.WriteTokenError(w, err)
+				return
+			}
+
+			reqCtx = reqCtx.WithAccessClaims(claims).LogField("user_id", claims.GetUserID())
+			next.ServeHTTP(w, r.WithContext(smart_context.ToContext(r.Context(), reqCtx)))
+		})
+	}
+}
+
+// RequestSmartContext возвращает ISmartContext запроса, а если middleware его ещё не создали —
+// копию sctx с контекстом запроса
+func RequestSmartContext(sctx smart_context.ISmartContext, r *http.Request) smart_context.ISmartContext {
+	if reqCtx, ok := smart_context.FromContext(r.Context()); ok {
+		return reqCtx
+	}
+	return sctx.WithContext(r.Context())
+}
+
+func bearerToken(r *http.Request) (string, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errMissingBearerToken
+	}
+	return strings.TrimSpace(token), nil
+}
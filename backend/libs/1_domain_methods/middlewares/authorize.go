@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+)
+
+// RequireScope пускает запросы, в access-токене которых есть все перечисленные scope.
+// Claims берутся из ISmartContext запроса, поэтому перед ним должен стоять Authenticate
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return requireClaims("scope "+strings.Join(scopes, " "), func(claims *types.AccessClaims) bool {
+		for _, scope := range scopes {
+			if !claims.HasScope(scope) {
+				return false
+			}
+		}
+		return true
+	}, `Bearer error="insufficient_scope", scope="`+strings.Join(scopes, " ")+`"`)
+}
+
+// RequireRole пускает запросы пользователей, у которых есть хотя бы одна из ролей
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return requireClaims("role "+strings.Join(roles, "|"), func(claims *types.AccessClaims) bool {
+		for _, role := range roles {
+			if claims.HasRole(role) {
+				return true
+			}
+		}
+		return false
+	}, `Bearer error="insufficient_scope"`)
+}
+
+// RequireAMR пускает пользователей, вход которых подтверждён хотя бы одним из способов (claim amr)
+func RequireAMR(methods ...string) func(http.Handler) http.Handler {
+	return requireClaims("amr "+strings.Join(methods, "|"), func(claims *types.AccessClaims) bool {
+		for _, method := range methods {
+			if claims.HasAMR(method) {
+				return true
+			}
+		}
+		return false
+	}, `Bearer error="insufficient_user_authentication"`)
+}
+
+func requireClaims(requirement string, allowed func(*types.AccessClaims) bool, challenge string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqCtx, ok := smart_context.FromContext(r.Context())
+			if !ok || reqCtx.GetAccessClaims() == nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			claims := reqCtx.GetAccessClaims()
+			if !allowed(claims) {
+				reqCtx.Warnf("Access denied for %s: %s required", claims.GetUserID(), requirement)
+				helpers.Audit(reqCtx, r, types.AuditEvent{
+					UserID:    claims.GetUserID(),
+					EventType: types.AuditAccessDenied,
+					Outcome:   types.AuditFailure,
+					Details:   types.Fields{"reason": requirement + " required", "path": r.URL.Path},
+				})
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+// RateLimitRule — лимиты одного роута. PerIP и PerUser задаются в формате types.ParseRateLimit
+// и переопределяются переменными RATE_LIMIT_<ROUTE>_PER_IP и RATE_LIMIT_<ROUTE>_PER_USER.
+type RateLimitRule struct {
+	Route   string
+	PerIP   string
+	PerUser string
+	// UserID достаёт id пользователя из запроса. Пустая строка — лимит по пользователю не применяется
+	UserID func(r *http.Request) string
+}
+
+func RateLimit(sctx smart_context.ISmartContext, rule RateLimitRule) func(http.Handler) http.Handler {
+	envPrefix := "RATE_LIMIT_" + strings.ToUpper(rule.Route)
+	perIP := loadRateLimit(sctx, envPrefix+"_PER_IP", rule.PerIP)
+	perUser := loadRateLimit(sctx, envPrefix+"_PER_USER", rule.PerUser)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limiter := sctx.GetRateLimiter()
+			if limiter == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var retryAfter time.Duration
+			allowed := true
+
+			check := func(key string, limit types.RateLimit) {
+				ok, wait, err := limiter.Allow(r.Context(), key, limit)
+				if err != nil {
+					// при недоступности хранилища лимитов не блокируем логин
+					sctx.Errorf("rate limiter error for %s: %v", key, err)
+					return
+				}
+				if !ok {
+					allowed = false
+					retryAfter = max(retryAfter, wait)
+				}
+			}
+
+			check(rule.Route+":ip:"+helpers.GetClientIP(r), perIP)
+			if rule.UserID != nil {
+				if userId := rule.UserID(r); userId != "" {
+					check(rule.Route+":user:"+userId, perUser)
+				}
+			}
+
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				sctx.Warnf("Rate limit exceeded on %s for IP=%s, retry after %ds", rule.Route, helpers.GetClientIP(r), seconds)
+				helpers.Audit(sctx, r, types.AuditEvent{
+					EventType: types.AuditRateLimitReached,
+					Outcome:   types.AuditFailure,
+					Details:   types.Fields{"route": rule.Route},
+				})
+				w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func loadRateLimit(sctx smart_context.ISmartContext, key, defaultValue string) types.RateLimit {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		value = defaultValue
+	}
+
+	limit, err := types.ParseRateLimit(value)
+	if err != nil {
+		sctx.Errorf("Invalid value for %s: %v. Using default: %s", key, err, defaultValue)
+		limit, _ = types.ParseRateLimit(defaultValue)
+	}
+	return limit
+}
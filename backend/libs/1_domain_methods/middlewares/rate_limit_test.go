@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"test-task3/libs/3_infrastructure/rate_limiter"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"testing"
+	"time"
+)
+
+// failingLimiter имитирует недоступное хранилище лимитов
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, types.RateLimit) (bool, time.Duration, error) {
+	return false, 0, errors.New("rate limit store is down")
+}
+
+func serveRateLimited(handler http.Handler, ip, userId string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/auth?userId="+userId, nil)
+	r.RemoteAddr = ip + ":40000"
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	sctx := smart_context.NewSmartContext().WithRateLimiter(rate_limiter.NewMemoryRateLimiter())
+	handler := RateLimit(sctx, RateLimitRule{
+		Route: "test_login",
+		PerIP: "2/m",
+		// запрос, отклонённый по IP, тоже расходует лимит пользователя
+		PerUser: "4/m",
+		UserID: func(r *http.Request) string {
+			return r.URL.Query().Get("userId")
+		},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for i := 0; i < 2; i++ {
+		if w := serveRateLimited(handler, "203.0.113.7", "user-1"); w.Code != http.StatusNoContent {
+			t.Fatalf("request %d status = %d, want %d", i+1, w.Code, http.StatusNoContent)
+		}
+	}
+
+	w := serveRateLimited(handler, "203.0.113.7", "user-1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	// 2/m пополняется токеном раз в 30 секунд
+	if retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || retryAfter < 29 || retryAfter > 30 {
+		t.Errorf("Retry-After = %q, want about 30", w.Header().Get("Retry-After"))
+	}
+
+	// другой IP проходит, пока не кончится лимит пользователя
+	if w := serveRateLimited(handler, "198.51.100.1", "user-1"); w.Code != http.StatusNoContent {
+		t.Errorf("another IP status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if w := serveRateLimited(handler, "198.51.100.2", "user-1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("user over limit status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if w := serveRateLimited(handler, "198.51.100.3", "user-2"); w.Code != http.StatusNoContent {
+		t.Errorf("another user status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestRateLimitAllowsWhenStoreFails(t *testing.T) {
+	sctx := smart_context.NewSmartContext().WithRateLimiter(failingLimiter{})
+	handler := RateLimit(sctx, RateLimitRule{Route: "test_login", PerIP: "1/m"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if w := serveRateLimited(handler, "203.0.113.7", ""); w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
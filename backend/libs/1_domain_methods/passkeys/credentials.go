@@ -0,0 +1,160 @@
+package passkeys
+
+import (
+	"errors"
+	"strings"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+)
+
+// CredentialView — passkey в ответах API, без ключа и служебных полей
+type CredentialView struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// webauthnUser — пользователь для библиотеки WebAuthn. User handle — id пользователя,
+// по нему при входе без email находится аккаунт
+type webauthnUser struct {
+	user        model.User
+	credentials []webauthn.Credential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	return []byte(u.user.ID)
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+func List(sctx smart_context.ISmartContext, userId string) ([]CredentialView, error) {
+	var rows []model.WebauthnCredential
+	if err := sctx.GetDB().Where("user_id = ?", userId).Order("created_at DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	views := make([]CredentialView, 0, len(rows))
+	for i := range rows {
+		views = append(views, newCredentialView(&rows[i]))
+	}
+	return views, nil
+}
+
+func Delete(sctx smart_context.ISmartContext, userId, id string) error {
+	result := sctx.GetDB().Where("id = ? AND user_id = ?", id, userId).Delete(&model.WebauthnCredential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCredentialNotFound
+	}
+	return nil
+}
+
+func loadUser(db *gorm.DB, userId string) (*webauthnUser, error) {
+	var user model.User
+	err := db.Where("id = ?", userId).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []model.WebauthnCredential
+	if err := db.Where("user_id = ?", userId).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	credentials := make([]webauthn.Credential, 0, len(rows))
+	for i := range rows {
+		credentials = append(credentials, toCredential(&rows[i]))
+	}
+	return &webauthnUser{user: user, credentials: credentials}, nil
+}
+
+func newCredentialRow(userId, name string, credential *webauthn.Credential) model.WebauthnCredential {
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return model.WebauthnCredential{
+		UserID:          userId,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		SignCount:       int64(credential.Authenticator.SignCount),
+		Transports:      strings.Join(transports, " "),
+		AttestationType: credential.AttestationType,
+		Aaguid:          credential.Authenticator.AAGUID,
+		Flags:           flagsValue(credential.Flags),
+		Name:            name,
+	}
+}
+
+func toCredential(row *model.WebauthnCredential) webauthn.Credential {
+	var transports []protocol.AuthenticatorTransport
+	for _, transport := range strings.Fields(row.Transports) {
+		transports = append(transports, protocol.AuthenticatorTransport(transport))
+	}
+
+	return webauthn.Credential{
+		ID:              row.CredentialID,
+		PublicKey:       row.PublicKey,
+		AttestationType: row.AttestationType,
+		Transport:       transports,
+		Flags:           webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(row.Flags)),
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    row.Aaguid,
+			SignCount: uint32(row.SignCount),
+		},
+	}
+}
+
+// flagsValue собирает флаги заново: после входа библиотека обновляет только булевы поля
+func flagsValue(flags webauthn.CredentialFlags) int16 {
+	var value protocol.AuthenticatorFlags
+	if flags.UserPresent {
+		value |= protocol.FlagUserPresent
+	}
+	if flags.UserVerified {
+		value |= protocol.FlagUserVerified
+	}
+	if flags.BackupEligible {
+		value |= protocol.FlagBackupEligible
+	}
+	if flags.BackupState {
+		value |= protocol.FlagBackupState
+	}
+	return int16(value)
+}
+
+func newCredentialView(row *model.WebauthnCredential) CredentialView {
+	view := CredentialView{
+		ID:         row.ID,
+		Name:       row.Name,
+		Transports: strings.Fields(row.Transports),
+		CreatedAt:  row.CreatedAt,
+	}
+	if !row.LastUsedAt.IsZero() {
+		view.LastUsedAt = &row.LastUsedAt
+	}
+	return view
+}
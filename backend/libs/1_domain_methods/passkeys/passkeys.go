@@ -0,0 +1,311 @@
+package passkeys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+)
+
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+)
+
+var (
+	ErrChallengeNotFound   = errors.New("webauthn challenge not found or expired")
+	ErrCredentialNotFound  = errors.New("passkey not found")
+	ErrCredentialExists    = errors.New("passkey is already registered")
+	ErrVerificationFailed  = errors.New("passkey verification failed")
+	ErrClonedAuthenticator = errors.New("authenticator signature counter did not increase")
+)
+
+type Config struct {
+	// RPID — домен сервиса, к которому привязываются passkeys (без схемы и порта)
+	RPID   string
+	RPName string
+	// Origins — origin страниц, с которых разрешены церемонии
+	Origins      []string
+	ChallengeTTL time.Duration
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	cfg := Config{
+		RPID:         "localhost",
+		RPName:       "test-task3",
+		Origins:      []string{"http://localhost:4000"},
+		ChallengeTTL: time.Duration(env_vars.GetEnvAsInt(sctx, "WEBAUTHN_CHALLENGE_TTL_SECONDS", 300)) * time.Second,
+	}
+	if rpId := os.Getenv("WEBAUTHN_RP_ID"); rpId != "" {
+		cfg.RPID = rpId
+	}
+	if rpName := os.Getenv("WEBAUTHN_RP_NAME"); rpName != "" {
+		cfg.RPName = rpName
+	}
+	if origins := os.Getenv("WEBAUTHN_RP_ORIGINS"); origins != "" {
+		cfg.Origins = strings.Split(origins, ",")
+		for i := range cfg.Origins {
+			cfg.Origins[i] = strings.TrimSpace(cfg.Origins[i])
+		}
+	}
+	return cfg
+}
+
+// RelyingParty проводит церемонии WebAuthn. Challenge хранятся в webauthn_challenges, поэтому
+// begin и finish могут попасть на разные инстансы
+type RelyingParty struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+func NewRelyingParty(cfg Config) (*RelyingParty, error) {
+	timeout := webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.ChallengeTTL, TimeoutUVD: cfg.ChallengeTTL}
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPName,
+		RPOrigins:     cfg.Origins,
+		// вход без пароля: ключ должен храниться на аутентификаторе и проверять пользователя (PIN, биометрия)
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+		Timeouts:              webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RelyingParty{webAuthn: webAuthn}, nil
+}
+
+// Ceremony — ответ begin: id challenge для finish и options для navigator.credentials
+type Ceremony struct {
+	ChallengeID string `json:"challenge_id"`
+	Options     any    `json:"options"`
+}
+
+// LoginResult — пользователь, вошедший по passkey, и способы входа для claim amr
+type LoginResult struct {
+	UserID string
+	AMR    []string
+}
+
+// BeginRegistration начинает регистрацию нового passkey пользователя. Уже зарегистрированные
+// ключи исключаются, чтобы аутентификатор не создал второй для того же аккаунта
+func (rp *RelyingParty) BeginRegistration(sctx smart_context.ISmartContext, userId string) (*Ceremony, error) {
+	user, err := loadUser(sctx.GetDB(), userId)
+	if err != nil {
+		return nil, err
+	}
+
+	creation, session, err := rp.beginRegistration(user)
+	if err != nil {
+		return nil, err
+	}
+
+	challengeId, err := saveSession(sctx, ceremonyRegistration, userId, session)
+	if err != nil {
+		return nil, err
+	}
+	return &Ceremony{ChallengeID: challengeId, Options: creation}, nil
+}
+
+// FinishRegistration проверяет ответ navigator.credentials.create() и сохраняет ключ
+func (rp *RelyingParty) FinishRegistration(sctx smart_context.ISmartContext, userId, challengeId, name string, response []byte) (*CredentialView, error) {
+	session, sessionUserId, err := takeSession(sctx, ceremonyRegistration, challengeId)
+	if err != nil {
+		return nil, err
+	}
+	if sessionUserId != userId {
+		return nil, ErrChallengeNotFound
+	}
+
+	user, err := loadUser(sctx.GetDB(), userId)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := rp.finishRegistration(user, session, response)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := sctx.GetDB().Model(&model.WebauthnCredential{}).Where("credential_id = ?", credential.ID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrCredentialExists
+	}
+
+	row := newCredentialRow(userId, strings.TrimSpace(name), credential)
+	if err := sctx.GetDB().Omit("LastUsedAt").Create(&row).Error; err != nil {
+		return nil, err
+	}
+
+	view := newCredentialView(&row)
+	return &view, nil
+}
+
+// BeginLogin начинает вход по passkey без email: аутентификатор сам предложит ключи для RPID
+func (rp *RelyingParty) BeginLogin(sctx smart_context.ISmartContext) (*Ceremony, error) {
+	assertion, session, err := rp.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, err
+	}
+
+	challengeId, err := saveSession(sctx, ceremonyLogin, "", session)
+	if err != nil {
+		return nil, err
+	}
+	return &Ceremony{ChallengeID: challengeId, Options: assertion}, nil
+}
+
+// FinishLogin проверяет ответ navigator.credentials.get(), обновляет счётчик подписей ключа
+// и возвращает пользователя
+func (rp *RelyingParty) FinishLogin(sctx smart_context.ISmartContext, challengeId string, response []byte) (*LoginResult, error) {
+	session, _, err := takeSession(sctx, ceremonyLogin, challengeId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, credential, err := rp.finishLogin(session, response, func(userHandle []byte) (*webauthnUser, error) {
+		return loadUser(sctx.GetDB(), string(userHandle))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := sctx.GetDB().Model(&model.WebauthnCredential{}).
+		Where("user_id = ? AND credential_id = ?", user.user.ID, credential.ID).
+		Updates(map[string]any{
+			"sign_count":   int64(credential.Authenticator.SignCount),
+			"flags":        flagsValue(credential.Flags),
+			"last_used_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrCredentialNotFound
+	}
+
+	return &LoginResult{UserID: user.user.ID, AMR: loginAMR(credential)}, nil
+}
+
+func (rp *RelyingParty) beginRegistration(user *webauthnUser) (*protocol.CredentialCreation, *webauthn.SessionData, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.credentials))
+	for _, credential := range user.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+	return rp.webAuthn.BeginRegistration(user, webauthn.WithExclusions(exclusions))
+}
+
+func (rp *RelyingParty) finishRegistration(user *webauthnUser, session *webauthn.SessionData, response []byte) (*webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerificationFailed, describe(err))
+	}
+
+	credential, err := rp.webAuthn.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrVerificationFailed, describe(err))
+	}
+	return credential, nil
+}
+
+// finishLogin проверяет подпись ключа. Подпись со счётчиком не больше сохранённого отклоняется:
+// так выглядит скопированный ключ
+func (rp *RelyingParty) finishLogin(session *webauthn.SessionData, response []byte, lookup func(userHandle []byte) (*webauthnUser, error)) (*webauthnUser, *webauthn.Credential, error) {
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrVerificationFailed, describe(err))
+	}
+
+	var user *webauthnUser
+	_, credential, err := rp.webAuthn.ValidatePasskeyLogin(func(rawId, userHandle []byte) (webauthn.User, error) {
+		user, err = lookup(userHandle)
+		return user, err
+	}, *session, parsed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrVerificationFailed, describe(err))
+	}
+	if credential.Authenticator.CloneWarning {
+		return nil, nil, ErrClonedAuthenticator
+	}
+
+	return user, credential, nil
+}
+
+// loginAMR: синхронизируемый passkey (флаг BE) — программный ключ, иначе аппаратный.
+// Проверка пользователя обязательна, поэтому вход по passkey двухфакторный
+func loginAMR(credential *webauthn.Credential) []string {
+	key := types.AMRHardwareKey
+	if credential.Flags.BackupEligible {
+		key = types.AMRSoftwareKey
+	}
+	return []string{key, types.AMRMultiFactor}
+}
+
+func saveSession(sctx smart_context.ISmartContext, ceremony, userId string, session *webauthn.SessionData) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := model.WebauthnChallenge{
+		Ceremony:    ceremony,
+		UserID:      userId,
+		SessionData: string(data),
+		ExpiresAt:   session.Expires,
+	}
+	if err := sctx.GetDB().Create(&challenge).Error; err != nil {
+		return "", err
+	}
+	return challenge.ID, nil
+}
+
+// takeSession достаёт и сразу удаляет challenge: второй finish с тем же id не пройдёт
+func takeSession(sctx smart_context.ISmartContext, ceremony, challengeId string) (*webauthn.SessionData, string, error) {
+	var challenge model.WebauthnChallenge
+	err := sctx.GetDB().Where("id = ? AND ceremony = ?", challengeId, ceremony).First(&challenge).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	result := sctx.GetDB().Where("id = ?", challenge.ID).Delete(&model.WebauthnChallenge{})
+	if result.Error != nil {
+		return nil, "", result.Error
+	}
+	if result.RowsAffected == 0 || time.Now().After(challenge.ExpiresAt) {
+		return nil, "", ErrChallengeNotFound
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(challenge.SessionData), &session); err != nil {
+		return nil, "", fmt.Errorf("decode webauthn session: %w", err)
+	}
+	return &session, challenge.UserID, nil
+}
+
+// describe достаёт из ошибки библиотеки причину, понятную клиенту
+func describe(err error) string {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) && protocolErr.Details != "" {
+		return protocolErr.Details
+	}
+	return err.Error()
+}
@@ -0,0 +1,375 @@
+package passkeys
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/types"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	testRPID   = "login.example.com"
+	testOrigin = "https://login.example.com"
+)
+
+var b64 = base64.RawURLEncoding
+
+// softAuthenticator — программный аутентификатор: ключ ES256 в памяти, attestation "none"
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	signCount    uint32
+	// extraFlags добавляются к UP и UV, например BE для синхронизируемого passkey
+	extraFlags protocol.AuthenticatorFlags
+	origin     string
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{key: key, credentialID: credentialID, origin: testOrigin}
+}
+
+// create отвечает на navigator.credentials.create() так, как это сделал бы браузер
+func (a *softAuthenticator) create(t *testing.T, creation *protocol.CredentialCreation) []byte {
+	t.Helper()
+
+	clientData := a.clientData(t, "webauthn.create", creation.Response.Challenge.String())
+
+	pub, err := a.key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := pub.Bytes()
+	coseKey, err := webauthncbor.Marshal(map[int]any{1: 2, 3: -7, -1: 1, -2: raw[1:33], -3: raw[33:65]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authData := a.authData(protocol.FlagAttestedCredentialData)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, coseKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credential(t, map[string]any{
+		"clientDataJSON":    b64.EncodeToString(clientData),
+		"attestationObject": b64.EncodeToString(attestationObject),
+		"transports":        []string{"internal", "hybrid"},
+	})
+}
+
+// get отвечает на navigator.credentials.get() с новым значением счётчика подписей
+func (a *softAuthenticator) get(t *testing.T, assertion *protocol.CredentialAssertion, userHandle []byte) []byte {
+	t.Helper()
+
+	a.signCount++
+	clientData := a.clientData(t, "webauthn.get", assertion.Response.Challenge.String())
+	authData := a.authData(0)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(slices.Clone(authData), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.credential(t, map[string]any{
+		"clientDataJSON":    b64.EncodeToString(clientData),
+		"authenticatorData": b64.EncodeToString(authData),
+		"signature":         b64.EncodeToString(signature),
+		"userHandle":        b64.EncodeToString(userHandle),
+	})
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony, challenge string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (a *softAuthenticator) authData(flags protocol.AuthenticatorFlags) []byte {
+	rpIdHash := sha256.Sum256([]byte(testRPID))
+	flags |= protocol.FlagUserPresent | protocol.FlagUserVerified | a.extraFlags
+
+	data := append(rpIdHash[:], byte(flags))
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+func (a *softAuthenticator) credential(t *testing.T, response map[string]any) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{
+		"id":       b64.EncodeToString(a.credentialID),
+		"rawId":    b64.EncodeToString(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestRelyingParty(t *testing.T) *RelyingParty {
+	t.Helper()
+
+	rp, err := NewRelyingParty(Config{
+		RPID:         testRPID,
+		RPName:       "test-task3",
+		Origins:      []string{testOrigin},
+		ChallengeTTL: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rp
+}
+
+func newTestUser(id string) *webauthnUser {
+	return &webauthnUser{user: model.User{ID: id, Email: id + "@example.com"}}
+}
+
+// register проводит регистрацию и возвращает ключ так, как он будет прочитан из базы
+func register(t *testing.T, rp *RelyingParty, user *webauthnUser, authenticator *softAuthenticator) webauthn.Credential {
+	t.Helper()
+
+	creation, session, err := rp.beginRegistration(user)
+	if err != nil {
+		t.Fatalf("beginRegistration() error = %v", err)
+	}
+
+	credential, err := rp.finishRegistration(user, session, authenticator.create(t, creation))
+	if err != nil {
+		t.Fatalf("finishRegistration() error = %v", err)
+	}
+
+	row := newCredentialRow(string(user.WebAuthnID()), "laptop", credential)
+	return toCredential(&row)
+}
+
+func login(t *testing.T, rp *RelyingParty, user *webauthnUser, authenticator *softAuthenticator) (*webauthnUser, *webauthn.Credential, error) {
+	t.Helper()
+
+	assertion, session, err := rp.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		t.Fatalf("BeginDiscoverableLogin() error = %v", err)
+	}
+
+	response := authenticator.get(t, assertion, user.WebAuthnID())
+	return rp.finishLogin(session, response, func(userHandle []byte) (*webauthnUser, error) {
+		if string(userHandle) != string(user.WebAuthnID()) {
+			return nil, ErrCredentialNotFound
+		}
+		return user, nil
+	})
+}
+
+func TestRegistrationAndLogin(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	user := newTestUser("user-1")
+	authenticator := newSoftAuthenticator(t)
+
+	stored := register(t, rp, user, authenticator)
+	if string(stored.ID) != string(authenticator.credentialID) {
+		t.Fatalf("stored credential id = %x, want %x", stored.ID, authenticator.credentialID)
+	}
+	if len(stored.Transport) != 2 || stored.Transport[0] != protocol.Internal {
+		t.Errorf("stored transports = %v", stored.Transport)
+	}
+	user.credentials = []webauthn.Credential{stored}
+
+	for i := 1; i <= 2; i++ {
+		loggedIn, credential, err := login(t, rp, user, authenticator)
+		if err != nil {
+			t.Fatalf("login #%d error = %v", i, err)
+		}
+		if loggedIn.user.ID != "user-1" {
+			t.Errorf("login #%d user = %s", i, loggedIn.user.ID)
+		}
+		if credential.Authenticator.SignCount != authenticator.signCount {
+			t.Errorf("login #%d sign count = %d, want %d", i, credential.Authenticator.SignCount, authenticator.signCount)
+		}
+		if amr := loginAMR(credential); !slices.Equal(amr, []string{types.AMRHardwareKey, types.AMRMultiFactor}) {
+			t.Errorf("login #%d amr = %v", i, amr)
+		}
+
+		// так FinishLogin сохраняет новый счётчик
+		user.credentials[0].Authenticator.SignCount = credential.Authenticator.SignCount
+		user.credentials[0].Flags = webauthn.NewCredentialFlags(protocol.AuthenticatorFlags(flagsValue(credential.Flags)))
+	}
+}
+
+func TestSyncedPasskeyIsSoftwareKey(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	user := newTestUser("user-2")
+	authenticator := newSoftAuthenticator(t)
+	authenticator.extraFlags = protocol.FlagBackupEligible | protocol.FlagBackupState
+
+	user.credentials = []webauthn.Credential{register(t, rp, user, authenticator)}
+
+	_, credential, err := login(t, rp, user, authenticator)
+	if err != nil {
+		t.Fatalf("login error = %v", err)
+	}
+	if amr := loginAMR(credential); !slices.Equal(amr, []string{types.AMRSoftwareKey, types.AMRMultiFactor}) {
+		t.Errorf("amr = %v", amr)
+	}
+}
+
+func TestRegistrationRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(a *softAuthenticator, session *webauthn.SessionData)
+	}{
+		{
+			name:   "foreign origin",
+			modify: func(a *softAuthenticator, _ *webauthn.SessionData) { a.origin = "https://evil.example.com" },
+		},
+		{
+			name: "challenge from another ceremony",
+			modify: func(_ *softAuthenticator, session *webauthn.SessionData) {
+				session.Challenge = "b3RoZXItY2hhbGxlbmdlLXZhbHVl"
+			},
+		},
+		{
+			name: "expired ceremony",
+			modify: func(_ *softAuthenticator, session *webauthn.SessionData) {
+				session.Expires = time.Now().Add(-time.Second)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := newTestRelyingParty(t)
+			user := newTestUser("user-3")
+			authenticator := newSoftAuthenticator(t)
+
+			creation, session, err := rp.beginRegistration(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.modify(authenticator, session)
+
+			_, err = rp.finishRegistration(user, session, authenticator.create(t, creation))
+			if !errors.Is(err, ErrVerificationFailed) {
+				t.Errorf("finishRegistration() error = %v, want %v", err, ErrVerificationFailed)
+			}
+		})
+	}
+}
+
+func TestLoginRejected(t *testing.T) {
+	t.Run("foreign origin", func(t *testing.T) {
+		rp := newTestRelyingParty(t)
+		user := newTestUser("user-4")
+		authenticator := newSoftAuthenticator(t)
+		user.credentials = []webauthn.Credential{register(t, rp, user, authenticator)}
+
+		authenticator.origin = "https://evil.example.com"
+		if _, _, err := login(t, rp, user, authenticator); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("login error = %v, want %v", err, ErrVerificationFailed)
+		}
+	})
+
+	t.Run("signed by another key", func(t *testing.T) {
+		rp := newTestRelyingParty(t)
+		user := newTestUser("user-5")
+		authenticator := newSoftAuthenticator(t)
+		user.credentials = []webauthn.Credential{register(t, rp, user, authenticator)}
+
+		impostor := newSoftAuthenticator(t)
+		impostor.credentialID = authenticator.credentialID
+		if _, _, err := login(t, rp, user, impostor); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("login error = %v, want %v", err, ErrVerificationFailed)
+		}
+	})
+
+	t.Run("credential of another user", func(t *testing.T) {
+		rp := newTestRelyingParty(t)
+		owner := newTestUser("user-6")
+		authenticator := newSoftAuthenticator(t)
+		owner.credentials = []webauthn.Credential{register(t, rp, owner, authenticator)}
+
+		// аутентификатор владельца выдаёт себя за другого пользователя без ключей
+		other := newTestUser("user-7")
+		if _, _, err := login(t, rp, other, authenticator); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("login error = %v, want %v", err, ErrVerificationFailed)
+		}
+	})
+
+	t.Run("signature counter did not increase", func(t *testing.T) {
+		rp := newTestRelyingParty(t)
+		user := newTestUser("user-8")
+		authenticator := newSoftAuthenticator(t)
+		user.credentials = []webauthn.Credential{register(t, rp, user, authenticator)}
+		user.credentials[0].Authenticator.SignCount = 10
+
+		if _, _, err := login(t, rp, user, authenticator); !errors.Is(err, ErrClonedAuthenticator) {
+			t.Errorf("login error = %v, want %v", err, ErrClonedAuthenticator)
+		}
+	})
+
+	t.Run("registration challenge used for login", func(t *testing.T) {
+		rp := newTestRelyingParty(t)
+		user := newTestUser("user-9")
+		authenticator := newSoftAuthenticator(t)
+		user.credentials = []webauthn.Credential{register(t, rp, user, authenticator)}
+
+		// сессия регистрации привязана к пользователю и не подходит для входа без email
+		_, session, err := rp.beginRegistration(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertion, _, err := rp.webAuthn.BeginDiscoverableLogin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := authenticator.get(t, assertion, user.WebAuthnID())
+
+		_, _, err = rp.finishLogin(session, response, func([]byte) (*webauthnUser, error) { return user, nil })
+		if !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("finishLogin() error = %v, want %v", err, ErrVerificationFailed)
+		}
+	})
+}
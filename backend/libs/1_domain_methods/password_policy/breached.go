@@ -0,0 +1,57 @@
+package password_policy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const breachPrefixLength = 5
+
+// BreachCount ищет пароль в локальной копии списка Have I Been Pwned и возвращает, сколько раз
+// он встречался в утечках. Файл каталога dir называется первыми 5 символами SHA-1 пароля
+// (с расширением .txt или без), строки в нём — "ОСТАТОК_ХЭША:ЧИСЛО". Наружу ничего не уходит,
+// а читается только один небольшой файл
+func BreachCount(dir, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachPrefixLength], hash[breachPrefixLength:]
+
+	file, err := openPrefixFile(dir, prefix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineSuffix, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !strings.EqualFold(lineSuffix, suffix) {
+			continue
+		}
+		// в списке без счётчиков само присутствие хэша — одна утечка
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 {
+			n = 1
+		}
+		return n, nil
+	}
+	return 0, scanner.Err()
+}
+
+func openPrefixFile(dir, prefix string) (*os.File, error) {
+	file, err := os.Open(filepath.Join(dir, prefix))
+	if errors.Is(err, fs.ErrNotExist) {
+		file, err = os.Open(filepath.Join(dir, prefix+".txt"))
+	}
+	return file, err
+}
@@ -0,0 +1,157 @@
+package password_policy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"test-task3/libs/1_domain_methods/passwords"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"unicode/utf8"
+)
+
+// Коды нарушений политики — по ним клиент показывает свой текст
+const (
+	CodeTooShort      = "too_short"
+	CodeTooLong       = "too_long"
+	CodeTooWeak       = "too_weak"
+	CodeContainsEmail = "contains_email"
+	CodeBreached      = "breached"
+)
+
+// maxScoredLength — оценивается только начало пароля: дальше оценка и так максимальна,
+// а время расчёта растёт кубически от длины
+const maxScoredLength = 64
+
+// Violation — одно нарушение политики
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationError — пароль не прошёл политику. Содержит все нарушения сразу, чтобы
+// пользователю не приходилось подбирать пароль по одной ошибке за раз
+type ValidationError struct {
+	Violations []Violation `json:"violations"`
+	// Score — оценка стойкости от 0 до 4
+	Score int `json:"score"`
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		codes = append(codes, v.Code)
+	}
+	return "password policy violated: " + strings.Join(codes, ", ")
+}
+
+type Config struct {
+	MinLength int
+	MaxLength int
+	// MinScore — минимальная оценка стойкости от 0 до 4, 0 — не проверять
+	MinScore    int
+	RejectEmail bool
+	// BreachedDir — каталог со списком утёкших паролей: файлы по первым 5 hex-символам SHA-1,
+	// в строках — остаток хэша и число утечек, как отдаёт range API Have I Been Pwned. Пустой — не проверять
+	BreachedDir string
+	// BreachedMinCount — сколько раз пароль должен встретиться в утечках, чтобы его отклонить
+	BreachedMinCount int
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	return Config{
+		MinLength:        env_vars.GetEnvAsInt(sctx, "PASSWORD_MIN_LENGTH", 10),
+		MaxLength:        env_vars.GetEnvAsInt(sctx, "PASSWORD_MAX_LENGTH", 128),
+		MinScore:         env_vars.GetEnvAsInt(sctx, "PASSWORD_MIN_SCORE", 3),
+		RejectEmail:      os.Getenv("PASSWORD_REJECT_EMAIL") != "false",
+		BreachedDir:      os.Getenv("PASSWORD_BREACHED_DIR"),
+		BreachedMinCount: env_vars.GetEnvAsInt(sctx, "PASSWORD_BREACHED_MIN_COUNT", 1),
+	}
+}
+
+// Validate проверяет пароль пользователя с указанным email. Нарушения политики возвращаются
+// как *ValidationError, другие ошибки — сбой чтения списка утёкших паролей
+func Validate(cfg Config, password, email string) error {
+	score := Score(truncate(password, maxScoredLength), emailInputs(email))
+	result := &ValidationError{Score: score}
+
+	length := utf8.RuneCountInString(password)
+	if length < cfg.MinLength {
+		result.add(CodeTooShort, fmt.Sprintf("password must be at least %d characters long", cfg.MinLength))
+	}
+	if cfg.MaxLength > 0 && length > cfg.MaxLength {
+		result.add(CodeTooLong, fmt.Sprintf("password must be at most %d characters long", cfg.MaxLength))
+	} else if maxBytes := passwords.MaxPasswordBytes(); maxBytes > 0 && len(password) > maxBytes {
+		result.add(CodeTooLong, fmt.Sprintf("password must be at most %d bytes long", maxBytes))
+	}
+
+	if cfg.RejectEmail && containsEmail(password, email) {
+		result.add(CodeContainsEmail, "password must not contain your email address")
+	}
+
+	if score < cfg.MinScore {
+		result.add(CodeTooWeak, "password is too easy to guess")
+	}
+
+	if cfg.BreachedDir != "" {
+		count, err := BreachCount(cfg.BreachedDir, password)
+		if err != nil {
+			return err
+		}
+		if count >= max(cfg.BreachedMinCount, 1) {
+			result.add(CodeBreached, "password has appeared in a data breach")
+		}
+	}
+
+	if len(result.Violations) > 0 {
+		return result
+	}
+	return nil
+}
+
+func (e *ValidationError) add(code, message string) {
+	e.Violations = append(e.Violations, Violation{Code: code, Message: message})
+}
+
+// truncate обрезает пароль до limit символов
+func truncate(password string, limit int) string {
+	runes := []rune(password)
+	if len(runes) <= limit {
+		return password
+	}
+	return string(runes[:limit])
+}
+
+// containsEmail — пароль содержит адрес целиком или его локальную часть
+func containsEmail(password, email string) bool {
+	password = strings.ToLower(password)
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+	if strings.Contains(password, email) {
+		return true
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	// короткая локальная часть вроде "al" встречается в паролях случайно
+	return utf8.RuneCountInString(local) >= 3 && strings.Contains(password, local)
+}
+
+// emailInputs — части email, которые оценка стойкости считает словами из словаря
+func emailInputs(email string) []string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil
+	}
+
+	local, domain, _ := strings.Cut(email, "@")
+	inputs := []string{email, local}
+	inputs = append(inputs, strings.FieldsFunc(local, func(r rune) bool {
+		return strings.ContainsRune("._+-", r)
+	})...)
+	if name, _, ok := strings.Cut(domain, "."); ok {
+		inputs = append(inputs, name)
+	}
+	return inputs
+}
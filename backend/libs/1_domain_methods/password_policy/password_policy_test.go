@@ -0,0 +1,121 @@
+package password_policy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	cases := []struct {
+		password string
+		maxScore int
+		minScore int
+	}{
+		{"password", 0, 0},
+		{"P@ssw0rd", 0, 0},
+		{"drowssap", 0, 0},
+		{"qwertyuiop", 0, 0},
+		{"abcdef123", 1, 0},
+		{"aaaaaaaaaaaa", 1, 0},
+		{"abcabcabcabc", 1, 0},
+		{"summer2024", 1, 0},
+		{"kT9#mPq2xZ", 4, 3},
+		{"jdF83-kLm0_qwP", 4, 4},
+		{"correct horse battery staple", 4, 4},
+	}
+
+	for _, c := range cases {
+		score := Score(c.password, nil)
+		if score < c.minScore || score > c.maxScore {
+			t.Errorf("Score(%q) = %d, want %d..%d", c.password, score, c.minScore, c.maxScore)
+		}
+	}
+}
+
+func TestScoreUserInputs(t *testing.T) {
+	password := "Zelenograd77!"
+	without := Score(password, nil)
+	with := Score(password, []string{"zelenograd"})
+	if with >= without {
+		t.Fatalf("Score with user input = %d, without = %d, want lower", with, without)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Config{MinLength: 10, MaxLength: 128, MinScore: 3, RejectEmail: true}
+
+	if err := Validate(cfg, "jdF83-kLm0_qwP", "alice@example.com"); err != nil {
+		t.Fatalf("Validate(strong) = %v, want nil", err)
+	}
+
+	cases := map[string]struct {
+		password string
+		codes    []string
+	}{
+		"short and weak": {"abc", []string{CodeTooShort, CodeTooWeak}},
+		"too long":       {strings.Repeat("x9#", 50), []string{CodeTooLong, CodeTooWeak}},
+		"email":          {"Alice@Example.com7", []string{CodeContainsEmail, CodeTooWeak}},
+		"local part":     {"dF83-alice-kLm0_qwP", []string{CodeContainsEmail}},
+	}
+	for name, c := range cases {
+		err := Validate(cfg, c.password, "alice@example.com")
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s: Validate() = %v, want *ValidationError", name, err)
+			continue
+		}
+
+		var codes []string
+		for _, v := range validationErr.Violations {
+			codes = append(codes, v.Code)
+			if v.Message == "" {
+				t.Errorf("%s: violation %s without message", name, v.Code)
+			}
+		}
+		if !slices.Equal(codes, c.codes) {
+			t.Errorf("%s: codes = %v, want %v", name, codes, c.codes)
+		}
+	}
+}
+
+func TestBreachCount(t *testing.T) {
+	dir := t.TempDir()
+	hash := sha1Hex("jdF83-kLm0_qwP")
+	// соседние строки с тем же префиксом, регистр остатка не важен
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n" + strings.ToLower(hash[5:]) + ":42\r\n"
+	if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := BreachCount(dir, "jdF83-kLm0_qwP")
+	if err != nil || count != 42 {
+		t.Fatalf("BreachCount(listed) = %d, %v, want 42", count, err)
+	}
+
+	count, err = BreachCount(dir, "another-password")
+	if err != nil || count != 0 {
+		t.Fatalf("BreachCount(unlisted) = %d, %v, want 0", count, err)
+	}
+
+	cfg := Config{BreachedDir: dir, BreachedMinCount: 10}
+	var validationErr *ValidationError
+	if err := Validate(cfg, "jdF83-kLm0_qwP", ""); !errors.As(err, &validationErr) || validationErr.Violations[0].Code != CodeBreached {
+		t.Fatalf("Validate(breached) = %v, want %s", err, CodeBreached)
+	}
+
+	cfg.BreachedMinCount = 100
+	if err := Validate(cfg, "jdF83-kLm0_qwP", ""); err != nil {
+		t.Fatalf("Validate(below min count) = %v, want nil", err)
+	}
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,389 @@
+package password_policy
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Оценка стойкости в духе zxcvbn: пароль разбивается на фрагменты — слово из словаря, в том числе
+// перевёрнутое и с заменами вроде @ вместо a, последовательность, ряд клавиатуры, повтор, год
+// или полный перебор. Для каждого фрагмента оценивается число попыток, и из всех разбиений
+// берётся самое дешёвое для атакующего. Оценка — порядок этого числа, сведённый к шкале 0–4
+
+//go:embed common_passwords.txt
+var commonPasswordsList string
+
+// commonPasswords — слово → место в списке частых паролей, чем выше, тем раньше его пробуют
+var commonPasswords = rankedDictionary(strings.Fields(commonPasswordsList))
+
+const (
+	minWordLength = 3
+	// bruteforceCardinality — попыток на символ, когда фрагмент ни на что не похож
+	bruteforceCardinality = 10
+	minGuessesSingleChar  = 10
+	minGuessesMultiChar   = 50
+)
+
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+var l33tTable = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+// fragment — оценка подстроки [start, end)
+type fragment struct {
+	start, end int
+	guesses    float64
+}
+
+// Score оценивает стойкость пароля от 0 (угадывается сразу) до 4 (очень стойкий).
+// userInputs — слова, которые атакующий знает о пользователе, например части email
+func Score(password string, userInputs []string) int {
+	e := &estimator{userDictionary: rankedDictionary(userInputs), blockGuesses: map[string]float64{}}
+	guesses := e.guesses([]rune(password))
+
+	switch {
+	case guesses < 1e3+5:
+		return 0
+	case guesses < 1e6+5:
+		return 1
+	case guesses < 1e8+5:
+		return 2
+	case guesses < 1e10+5:
+		return 3
+	default:
+		return 4
+	}
+}
+
+type estimator struct {
+	userDictionary map[string]int
+	// blockGuesses — оценки звеньев повторов: звено оценивается как отдельный пароль,
+	// и без кэша вложенные повторы вроде aaaa…a считались бы заново на каждом уровне
+	blockGuesses map[string]float64
+}
+
+// guesses возвращает число попыток для самого дешёвого разбиения пароля на фрагменты.
+// Как в zxcvbn, к произведению оценок фрагментов добавляется k! за выбор их порядка
+func (e *estimator) guesses(password []rune) float64 {
+	n := len(password)
+	if n == 0 {
+		return 1
+	}
+
+	byEnd := make([][]fragment, n+1)
+	for _, f := range e.findFragments(password) {
+		byEnd[f.end] = append(byEnd[f.end], f)
+	}
+
+	// best[end][k] — log10 попыток для префикса длины end, разбитого на k фрагментов
+	best := make([][]float64, n+1)
+	for end := range best {
+		best[end] = make([]float64, n+1)
+		for k := range best[end] {
+			best[end][k] = math.Inf(1)
+		}
+	}
+	best[0][0] = 0
+
+	for end := 1; end <= n; end++ {
+		for k := 1; k <= end; k++ {
+			for start := 0; start < end; start++ {
+				if prev := best[start][k-1]; !math.IsInf(prev, 1) {
+					cost := prev + float64(end-start)*math.Log10(bruteforceCardinality)
+					best[end][k] = min(best[end][k], cost)
+				}
+			}
+			for _, f := range byEnd[end] {
+				if prev := best[f.start][k-1]; !math.IsInf(prev, 1) {
+					best[end][k] = min(best[end][k], prev+math.Log10(f.guesses))
+				}
+			}
+		}
+	}
+
+	total := math.Inf(1)
+	for k := 1; k <= n; k++ {
+		if !math.IsInf(best[n][k], 1) {
+			total = min(total, best[n][k]+logFactorial(k))
+		}
+	}
+	return math.Pow(10, total)
+}
+
+func (e *estimator) findFragments(password []rune) []fragment {
+	var fragments []fragment
+	fragments = append(fragments, dictionaryFragments(password, e.userDictionary)...)
+	fragments = append(fragments, sequenceFragments(password)...)
+	fragments = append(fragments, keyboardFragments(password)...)
+	fragments = append(fragments, e.repeatFragments(password)...)
+	fragments = append(fragments, yearFragments(password)...)
+
+	for i := range fragments {
+		minGuesses := float64(minGuessesMultiChar)
+		if fragments[i].end-fragments[i].start == 1 {
+			minGuesses = minGuessesSingleChar
+		}
+		fragments[i].guesses = max(fragments[i].guesses, minGuesses)
+	}
+	return fragments
+}
+
+// dictionaryFragments ищет слова из словарей как есть, задом наперёд и с l33t-заменами
+func dictionaryFragments(password []rune, userDictionary map[string]int) []fragment {
+	n := len(password)
+	lower := []rune(strings.ToLower(string(password)))
+
+	reversed := make([]rune, n)
+	for i, r := range lower {
+		reversed[n-1-i] = r
+	}
+
+	unleeted := make([]rune, n)
+	substituted := make([]bool, n)
+	for i, r := range lower {
+		unleeted[i] = r
+		if plain, ok := l33tTable[r]; ok {
+			unleeted[i] = plain
+			substituted[i] = true
+		}
+	}
+
+	var fragments []fragment
+	for start := 0; start < n; start++ {
+		for end := start + minWordLength; end <= n; end++ {
+			variations := uppercaseVariations(password[start:end])
+
+			if rank, ok := lookupWord(string(lower[start:end]), userDictionary); ok {
+				fragments = append(fragments, fragment{start, end, float64(rank) * variations})
+			}
+
+			// в перевёрнутой строке подстрока [n-end, n-start) — это [start, end) исходной
+			if rank, ok := lookupWord(string(reversed[n-end:n-start]), userDictionary); ok {
+				fragments = append(fragments, fragment{start, end, float64(rank) * variations * 2})
+			}
+
+			subs := 0
+			for _, s := range substituted[start:end] {
+				if s {
+					subs++
+				}
+			}
+			if subs > 0 {
+				if rank, ok := lookupWord(string(unleeted[start:end]), userDictionary); ok {
+					fragments = append(fragments, fragment{start, end, float64(rank) * variations * math.Pow(2, float64(subs))})
+				}
+			}
+		}
+	}
+	return fragments
+}
+
+func lookupWord(word string, userDictionary map[string]int) (int, bool) {
+	rank, ok := commonPasswords[word]
+	if userRank, userOk := userDictionary[word]; userOk && (!ok || userRank < rank) {
+		return userRank, true
+	}
+	return rank, ok
+}
+
+// sequenceFragments ищет отрезки вроде abcd, 4321, XYZ — соседние символы одного класса с шагом ±1
+func sequenceFragments(password []rune) []fragment {
+	var fragments []fragment
+	n := len(password)
+
+	for start := 0; start < n-1; {
+		delta := password[start+1] - password[start]
+		end := start + 1
+		if delta == 1 || delta == -1 {
+			for end < n && password[end]-password[end-1] == delta && sameClass(password[start], password[end]) {
+				end++
+			}
+		}
+
+		if end-start >= minWordLength {
+			for i := start; i < end; i++ {
+				for j := i + minWordLength; j <= end; j++ {
+					fragments = append(fragments, fragment{i, j, sequenceGuesses(password[i], j-i, delta < 0)})
+				}
+			}
+			start = end - 1
+		} else {
+			start++
+		}
+	}
+	return fragments
+}
+
+func sequenceGuesses(first rune, length int, descending bool) float64 {
+	base := 26.0
+	switch {
+	case strings.ContainsRune("aAzZ019", first):
+		// с этих символов последовательности пробуют в первую очередь
+		base = 4
+	case unicode.IsDigit(first):
+		base = 10
+	}
+	if descending {
+		base *= 2
+	}
+	return base * float64(length)
+}
+
+func sameClass(a, b rune) bool {
+	switch {
+	case unicode.IsDigit(a):
+		return unicode.IsDigit(b)
+	case unicode.IsLower(a):
+		return unicode.IsLower(b)
+	case unicode.IsUpper(a):
+		return unicode.IsUpper(b)
+	}
+	return false
+}
+
+// keyboardFragments ищет куски рядов клавиатуры вроде qwer или lkjh
+func keyboardFragments(password []rune) []fragment {
+	var fragments []fragment
+	lower := []rune(strings.ToLower(string(password)))
+	keys := 0
+	for _, row := range keyboardRows {
+		keys += len(row)
+	}
+
+	for start := 0; start < len(lower); start++ {
+		for end := start + minWordLength; end <= len(lower); end++ {
+			chunk := string(lower[start:end])
+			for _, row := range keyboardRows {
+				if strings.Contains(row, chunk) {
+					fragments = append(fragments, fragment{start, end, float64(keys * (end - start))})
+				} else if strings.Contains(reverseString(row), chunk) {
+					fragments = append(fragments, fragment{start, end, float64(2 * keys * (end - start))})
+				}
+			}
+		}
+	}
+	return fragments
+}
+
+// repeatFragments ищет повторы вроде aaaa или abcabc; повтор стоит столько, сколько его звено, на число повторов
+func (e *estimator) repeatFragments(password []rune) []fragment {
+	var fragments []fragment
+	n := len(password)
+
+	for start := 0; start < n; start++ {
+		for size := 1; start+2*size <= n; size++ {
+			block := password[start : start+size]
+			repeats := 1
+			for start+(repeats+1)*size <= n && string(password[start+repeats*size:start+(repeats+1)*size]) == string(block) {
+				repeats++
+			}
+			// пара одинаковых символов — ещё не повтор
+			if repeats < 2 || (size == 1 && repeats < 3) {
+				continue
+			}
+
+			key := string(block)
+			guesses, ok := e.blockGuesses[key]
+			if !ok {
+				guesses = e.guesses(block)
+				e.blockGuesses[key] = guesses
+			}
+			fragments = append(fragments, fragment{start, start + repeats*size, guesses * float64(repeats)})
+		}
+	}
+	return fragments
+}
+
+// yearFragments ищет годы 1900–2099: их пробуют начиная с близких к текущему
+func yearFragments(password []rune) []fragment {
+	var fragments []fragment
+	currentYear := time.Now().Year()
+
+	for start := 0; start+4 <= len(password); start++ {
+		year := 0
+		for _, r := range password[start : start+4] {
+			if r < '0' || r > '9' {
+				year = -1
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+		if year < 1900 || year > 2099 {
+			continue
+		}
+
+		distance := currentYear - year
+		if distance < 0 {
+			distance = -distance
+		}
+		fragments = append(fragments, fragment{start, start + 4, float64(max(distance, 20))})
+	}
+	return fragments
+}
+
+// uppercaseVariations — во сколько раз заглавные буквы увеличивают перебор слова: Password и PASSWORD
+// пробуют сразу после password, а произвольный регистр — число способов расставить заглавные
+func uppercaseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		}
+	}
+
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(word[0]) || unicode.IsUpper(word[len(word)-1]))) {
+		return 2
+	}
+
+	variations := 0.0
+	for k := 1; k <= min(upper, lower); k++ {
+		variations += binomial(upper+lower, k)
+	}
+	return variations
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+func logFactorial(n int) float64 {
+	result := 0.0
+	for i := 2; i <= n; i++ {
+		result += math.Log10(float64(i))
+	}
+	return result
+}
+
+func rankedDictionary(words []string) map[string]int {
+	ranked := make(map[string]int, len(words))
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if _, ok := ranked[word]; !ok {
+			ranked[word] = i + 1
+		}
+	}
+	return ranked
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
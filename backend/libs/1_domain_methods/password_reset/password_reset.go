@@ -0,0 +1,158 @@
+package password_reset
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/password_policy"
+	"test-task3/libs/1_domain_methods/passwords"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrTokenInvalid = errors.New("invalid or expired password reset token")
+
+type Config struct {
+	TokenTTL time.Duration
+	// ResetURL — страница сброса пароля, токен добавляется параметром token. Пустой — в письме только токен
+	ResetURL string
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	return Config{
+		TokenTTL: time.Duration(env_vars.GetEnvAsInt(sctx, "PASSWORD_RESET_TOKEN_TTL_SECONDS", 1800)) * time.Second,
+		ResetURL: os.Getenv("PASSWORD_RESET_URL"),
+	}
+}
+
+// RequestReset выпускает токен сброса пароля и отправляет его письмом. Для неизвестного email
+// ничего не делает и возвращает пустой userId — ответ клиенту от этого не зависит
+func RequestReset(sctx smart_context.ISmartContext, cfg Config, email, clientIP string) (string, error) {
+	var user model.User
+	err := sctx.GetDB().Where("lower(email) = ?", email).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	token, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		return user.ID, err
+	}
+
+	resetToken := model.PasswordResetToken{
+		TokenHash: helpers.HashToken(token),
+		UserID:    user.ID,
+		IPAddress: clientIP,
+		ExpiresAt: time.Now().Add(cfg.TokenTTL),
+	}
+	if err := sctx.GetDB().Omit("UsedAt").Create(&resetToken).Error; err != nil {
+		return user.ID, err
+	}
+
+	mailer := sctx.GetMailer()
+	if mailer == nil {
+		return user.ID, errors.New("mailer is not configured")
+	}
+	return user.ID, mailer.Send(sctx.GetContext(), resetMessage(cfg, user.Email, token))
+}
+
+// RevokeFunc отзывает токены и сессии пользователя, выпущенные до before. Вызывается в транзакции смены пароля
+type RevokeFunc func(txCtx smart_context.ISmartContext, userId string, before time.Time) error
+
+// Reset меняет пароль по токену из письма и гасит все неиспользованные токены пользователя.
+// Сессии отзываются revoke в той же транзакции: если отзыв не удался, пароль не меняется.
+// Пароль, не прошедший политику, — *password_policy.ValidationError, токен при этом не гасится.
+// userId заполнен и при ErrTokenInvalid, если токен найден, — чтобы записать неудачу в аудит
+func Reset(sctx smart_context.ISmartContext, policy password_policy.Config, token, password string, revoke RevokeFunc) (string, error) {
+	var resetToken model.PasswordResetToken
+	err := sctx.GetDB().Where("token_hash = ?", helpers.HashToken(token)).First(&resetToken).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", ErrTokenInvalid
+	}
+	if err != nil {
+		return "", err
+	}
+	if !resetToken.UsedAt.IsZero() || time.Now().After(resetToken.ExpiresAt) {
+		return resetToken.UserID, ErrTokenInvalid
+	}
+
+	var user model.User
+	if err := sctx.GetDB().Where("id = ?", resetToken.UserID).First(&user).Error; err != nil {
+		return resetToken.UserID, err
+	}
+	if err := password_policy.Validate(policy, password, user.Email); err != nil {
+		return resetToken.UserID, err
+	}
+
+	passwordHash, err := passwords.Hash(password)
+	if err != nil {
+		return resetToken.UserID, err
+	}
+
+	err = sctx.GetDB().Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// условие на used_at — чтобы из двух одновременных запросов с одним токеном прошёл один
+		result := tx.Model(&model.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", resetToken.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTokenInvalid
+		}
+
+		err := tx.Model(&model.User{}).Where("id = ?", resetToken.UserID).Updates(map[string]any{
+			"password":   passwordHash,
+			"updated_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", resetToken.UserID).
+			Update("used_at", now).Error
+		if err != nil {
+			return err
+		}
+		return revoke(sctx.WithDB(tx), resetToken.UserID, now)
+	})
+	if err != nil {
+		return resetToken.UserID, err
+	}
+
+	return resetToken.UserID, nil
+}
+
+func resetMessage(cfg Config, email, token string) types.MailMessage {
+	link := token
+	if cfg.ResetURL != "" {
+		if resetURL, err := url.Parse(cfg.ResetURL); err == nil {
+			query := resetURL.Query()
+			query.Set("token", token)
+			resetURL.RawQuery = query.Encode()
+			link = resetURL.String()
+		}
+	}
+
+	return types.MailMessage{
+		To:      email,
+		Subject: "Password reset",
+		Text: fmt.Sprintf("Someone requested a password reset for your account.\n\n"+
+			"To set a new password, use this within %d minutes:\n%s\n\n"+
+			"If it wasn't you, ignore this email: your password has not been changed.\n",
+			int(cfg.TokenTTL.Minutes()), link),
+	}
+}
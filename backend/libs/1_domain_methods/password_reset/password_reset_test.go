@@ -0,0 +1,222 @@
+package password_reset
+
+import (
+	"errors"
+	"strings"
+	"test-task3/libs/1_domain_methods/helpers"
+	"test-task3/libs/1_domain_methods/password_policy"
+	"test-task3/libs/1_domain_methods/passwords"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/test_db"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	testUserID   = "user-1"
+	testEmail    = "alice@example.com"
+	testPassword = "old-password-hash"
+	newPassword  = "correct horse battery staple"
+)
+
+var testPolicy = password_policy.Config{MinLength: 10, MaxLength: 128}
+
+func newTestContext(t *testing.T) (smart_context.ISmartContext, *gorm.DB, *test_db.Mailer) {
+	t.Helper()
+
+	db := test_db.Open(t, "users", "password_reset_tokens")
+	if err := db.Create(&model.User{ID: testUserID, Email: testEmail, Password: testPassword}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	mailer := &test_db.Mailer{}
+	sctx := smart_context.NewSmartContext().WithDB(db).WithMailer(mailer)
+	return sctx, db, mailer
+}
+
+// createToken сохраняет токен сброса так же, как RequestReset, и возвращает сам токен
+func createToken(t *testing.T, db *gorm.DB, expiresAt time.Time) string {
+	t.Helper()
+
+	token, err := helpers.GenerateRandomHex(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := model.PasswordResetToken{TokenHash: helpers.HashToken(token), UserID: testUserID, IPAddress: "203.0.113.7", ExpiresAt: expiresAt}
+	if err := db.Omit("UsedAt").Create(&row).Error; err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func loadUser(t *testing.T, db *gorm.DB) model.User {
+	t.Helper()
+
+	var user model.User
+	if err := db.Where("id = ?", testUserID).First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func unusedTokens(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&model.PasswordResetToken{}).Where("user_id = ? AND used_at IS NULL", testUserID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func noRevoke(smart_context.ISmartContext, string, time.Time) error {
+	return nil
+}
+
+func TestRequestReset(t *testing.T) {
+	sctx, db, mailer := newTestContext(t)
+	cfg := Config{TokenTTL: 30 * time.Minute, ResetURL: "https://app.example.com/reset?lang=ru"}
+
+	userId, err := RequestReset(sctx, cfg, testEmail, "203.0.113.7")
+	if err != nil || userId != testUserID {
+		t.Fatalf("RequestReset() = %q, %v, want %q", userId, err, testUserID)
+	}
+	if len(mailer.Sent) != 1 || mailer.Sent[0].To != testEmail {
+		t.Fatalf("sent = %+v, want one message to %s", mailer.Sent, testEmail)
+	}
+
+	// в письме ссылка на страницу сброса, параметры страницы сохраняются
+	link := mailer.LastLink(t, testEmail)
+	if link.Query().Get("lang") != "ru" {
+		t.Fatalf("reset link %s lost the page parameters", link)
+	}
+
+	// в базе только хэш токена
+	var row model.PasswordResetToken
+	if err := db.Where("token_hash = ?", helpers.HashToken(link.Query().Get("token"))).First(&row).Error; err != nil {
+		t.Fatalf("token from the link is not stored: %v", err)
+	}
+	if row.UserID != testUserID || row.IPAddress != "203.0.113.7" || !row.UsedAt.IsZero() {
+		t.Errorf("stored token = %+v", row)
+	}
+	if ttl := time.Until(row.ExpiresAt); ttl <= 29*time.Minute || ttl > cfg.TokenTTL {
+		t.Errorf("token expires in %s, want about %s", ttl, cfg.TokenTTL)
+	}
+
+	// для неизвестного адреса ничего не создаётся и не отправляется
+	userId, err = RequestReset(sctx, cfg, "nobody@example.com", "203.0.113.7")
+	if err != nil || userId != "" {
+		t.Errorf("RequestReset(unknown) = %q, %v, want empty", userId, err)
+	}
+	if len(mailer.Sent) != 1 {
+		t.Errorf("sent %d messages, want 1", len(mailer.Sent))
+	}
+}
+
+func TestResetMessageWithoutURL(t *testing.T) {
+	message := resetMessage(Config{TokenTTL: 30 * time.Minute}, testEmail, "abc123")
+	if !strings.Contains(message.Text, "\nabc123\n") || !strings.Contains(message.Text, "30 minutes") {
+		t.Errorf("message text = %q", message.Text)
+	}
+}
+
+func TestReset(t *testing.T) {
+	sctx, db, _ := newTestContext(t)
+	token := createToken(t, db, time.Now().Add(time.Hour))
+	createToken(t, db, time.Now().Add(time.Hour))
+
+	var revokedUser string
+	revoke := func(txCtx smart_context.ISmartContext, userId string, before time.Time) error {
+		revokedUser = userId
+		return nil
+	}
+
+	userId, err := Reset(sctx, testPolicy, token, newPassword, revoke)
+	if err != nil || userId != testUserID {
+		t.Fatalf("Reset() = %q, %v, want %q", userId, err, testUserID)
+	}
+	if revokedUser != testUserID {
+		t.Errorf("revoked sessions of %q, want %q", revokedUser, testUserID)
+	}
+	if ok, _, err := passwords.Verify(newPassword, loadUser(t, db).Password); err != nil || !ok {
+		t.Errorf("new password does not verify: %v", err)
+	}
+	// остальные выпущенные токены сброса гасятся вместе с использованным
+	if n := unusedTokens(t, db); n != 0 {
+		t.Errorf("%d reset tokens left unused, want 0", n)
+	}
+
+	// токен одноразовый
+	userId, err = Reset(sctx, testPolicy, token, "another long password", noRevoke)
+	if !errors.Is(err, ErrTokenInvalid) || userId != testUserID {
+		t.Errorf("second Reset() = %q, %v, want %q, %v", userId, err, testUserID, ErrTokenInvalid)
+	}
+}
+
+func TestResetRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		token     func(token string) string
+		password  string
+		revokeErr error
+		wantErr   func(err error) bool
+	}{
+		{
+			name:      "unknown token",
+			expiresAt: time.Now().Add(time.Hour),
+			token:     func(string) string { return "unknown" },
+			password:  newPassword,
+			wantErr:   func(err error) bool { return errors.Is(err, ErrTokenInvalid) },
+		},
+		{
+			name:      "expired token",
+			expiresAt: time.Now().Add(-time.Second),
+			password:  newPassword,
+			wantErr:   func(err error) bool { return errors.Is(err, ErrTokenInvalid) },
+		},
+		{
+			name:      "weak password",
+			expiresAt: time.Now().Add(time.Hour),
+			password:  "short",
+			wantErr: func(err error) bool {
+				var validationErr *password_policy.ValidationError
+				return errors.As(err, &validationErr)
+			},
+		},
+		{
+			// пароль не должен смениться, если старые сессии не удалось завершить
+			name:      "revoke failure",
+			expiresAt: time.Now().Add(time.Hour),
+			password:  newPassword,
+			revokeErr: errors.New("revocation store is down"),
+			wantErr:   func(err error) bool { return err != nil && err.Error() == "revocation store is down" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sctx, db, _ := newTestContext(t)
+			token := createToken(t, db, tt.expiresAt)
+			if tt.token != nil {
+				token = tt.token(token)
+			}
+
+			_, err := Reset(sctx, testPolicy, token, tt.password, func(smart_context.ISmartContext, string, time.Time) error {
+				return tt.revokeErr
+			})
+			if !tt.wantErr(err) {
+				t.Fatalf("Reset() error = %v", err)
+			}
+			if password := loadUser(t, db).Password; password != testPassword {
+				t.Errorf("password changed to %q", password)
+			}
+			if n := unusedTokens(t, db); n != 1 {
+				t.Errorf("%d reset tokens left unused, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package passwords
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// DefaultBcryptCost — для новых bcrypt-хэшей, если bcrypt выбран алгоритмом. Старые хэши посчитаны с bcrypt.DefaultCost
+const DefaultBcryptCost = 12
+
+// bcrypt не принимает пароли длиннее 72 байт
+const bcryptMaxPasswordBytes = 72
+
+// BcryptHasher — хэши в формате $2a$/$2b$/$2y$, которые были у всех пользователей до argon2id
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) (*BcryptHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &BcryptHasher{cost: cost}, nil
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Identifies(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *BcryptHasher) Verify(password, encoded string) (bool, bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("%w: %v", ErrMalformedHash, err)
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return false, false, fmt.Errorf("%w: %v", ErrMalformedHash, err)
+	}
+	return true, cost != h.cost, nil
+}
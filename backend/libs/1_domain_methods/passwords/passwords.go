@@ -0,0 +1,162 @@
+package passwords
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// PasswordHasher — один алгоритм хэширования паролей. Хэши — строки PHC (или Modular Crypt Format
+// у bcrypt), поэтому по префиксу видно, каким алгоритмом и с какими параметрами посчитан хэш
+type PasswordHasher interface {
+	// Hash хэширует пароль текущими параметрами
+	Hash(password string) (string, error)
+	// Identifies сообщает, что хэш посчитан этим алгоритмом
+	Identifies(encoded string) bool
+	// Verify сравнивает пароль с хэшем. needsRehash — хэш посчитан с другими параметрами
+	Verify(password, encoded string) (ok bool, needsRehash bool, err error)
+}
+
+type Config struct {
+	// Algorithm — алгоритм новых хэшей; хэши остальных алгоритмов проверяются и пересчитываются при входе
+	Algorithm  string
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	algorithm := strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM"))
+	if algorithm == "" {
+		algorithm = AlgorithmArgon2id
+	}
+
+	return Config{
+		Algorithm: algorithm,
+		Argon2: Argon2Params{
+			Memory:      uint32(env_vars.GetEnvAsInt(sctx, "PASSWORD_ARGON2_MEMORY_KIB", int(DefaultArgon2Params.Memory))),
+			Iterations:  uint32(env_vars.GetEnvAsInt(sctx, "PASSWORD_ARGON2_ITERATIONS", int(DefaultArgon2Params.Iterations))),
+			Parallelism: uint8(env_vars.GetEnvAsInt(sctx, "PASSWORD_ARGON2_PARALLELISM", int(DefaultArgon2Params.Parallelism))),
+			SaltLength:  DefaultArgon2Params.SaltLength,
+			KeyLength:   DefaultArgon2Params.KeyLength,
+		},
+		BcryptCost: env_vars.GetEnvAsInt(sctx, "PASSWORD_BCRYPT_COST", DefaultBcryptCost),
+	}
+}
+
+// Hasher хэширует пароли выбранным алгоритмом и проверяет хэши всех поддерживаемых
+type Hasher struct {
+	current PasswordHasher
+	all     []PasswordHasher
+	// dummyHash — хэш текущим алгоритмом для проверки, когда пользователь не найден. Считается
+	// при первой такой проверке, чтобы не тратить время и память при старте
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+func NewHasher(cfg Config) (*Hasher, error) {
+	argon2id, err := NewArgon2idHasher(cfg.Argon2)
+	if err != nil {
+		return nil, err
+	}
+	bcryptHasher, err := NewBcryptHasher(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hasher{all: []PasswordHasher{argon2id, bcryptHasher}}
+	switch cfg.Algorithm {
+	case AlgorithmArgon2id:
+		h.current = argon2id
+	case AlgorithmBcrypt:
+		h.current = bcryptHasher
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", cfg.Algorithm)
+	}
+	return h, nil
+}
+
+// Hash хэширует пароль текущим алгоритмом
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify проверяет пароль хэшем любого поддерживаемого алгоритма. needsRehash — хэш посчитан
+// другим алгоритмом или с другими параметрами, и после входа его стоит заменить на Hash(password)
+func (h *Hasher) Verify(password, encoded string) (bool, bool, error) {
+	for _, hasher := range h.all {
+		if !hasher.Identifies(encoded) {
+			continue
+		}
+		ok, needsRehash, err := hasher.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, needsRehash || hasher != h.current, nil
+	}
+	return false, false, ErrUnknownHash
+}
+
+// MaxPasswordBytes — наибольшая длина пароля в байтах, которую принимает текущий алгоритм; 0 — без ограничения
+func (h *Hasher) MaxPasswordBytes() int {
+	if _, ok := h.current.(*BcryptHasher); ok {
+		return bcryptMaxPasswordBytes
+	}
+	return 0
+}
+
+// VerifyDummy занимает столько же времени, сколько Verify для существующего пользователя,
+// чтобы время ответа не выдавало, есть ли такой email
+func (h *Hasher) VerifyDummy(password string) {
+	h.dummyOnce.Do(func() {
+		h.dummyHash, _ = h.current.Hash("dummy-password")
+	})
+	h.Verify(password, h.dummyHash)
+}
+
+var defaultHasher = mustHasher(Config{
+	Algorithm:  AlgorithmArgon2id,
+	Argon2:     DefaultArgon2Params,
+	BcryptCost: DefaultBcryptCost,
+})
+
+// Configure задаёт алгоритм и параметры хэширования паролей для всего процесса
+func Configure(cfg Config) error {
+	h, err := NewHasher(cfg)
+	if err != nil {
+		return err
+	}
+	defaultHasher = h
+	return nil
+}
+
+func Hash(password string) (string, error) {
+	return defaultHasher.Hash(password)
+}
+
+func Verify(password, encoded string) (bool, bool, error) {
+	return defaultHasher.Verify(password, encoded)
+}
+
+func VerifyDummy(password string) {
+	defaultHasher.VerifyDummy(password)
+}
+
+func MaxPasswordBytes() int {
+	return defaultHasher.MaxPasswordBytes()
+}
+
+func mustHasher(cfg Config) *Hasher {
+	h, err := NewHasher(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
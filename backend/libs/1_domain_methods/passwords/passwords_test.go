@@ -0,0 +1,165 @@
+package passwords
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// лёгкие параметры, чтобы тесты не тратили по 64 MiB на хэш
+var testArgon2Params = Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestHasher(t *testing.T, algorithm string, argon2Params Argon2Params, bcryptCost int) *Hasher {
+	t.Helper()
+	h, err := NewHasher(Config{Algorithm: algorithm, Argon2: argon2Params, BcryptCost: bcryptCost})
+	if err != nil {
+		t.Fatalf("NewHasher() error = %v", err)
+	}
+	return h
+}
+
+func TestArgon2idHash(t *testing.T) {
+	h := newTestHasher(t, AlgorithmArgon2id, testArgon2Params, bcrypt.MinCost)
+
+	encoded, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	phc := regexp.MustCompile(`^\$argon2id\$v=19\$m=1024,t=1,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`)
+	if !phc.MatchString(encoded) {
+		t.Errorf("Hash() = %q, want PHC argon2id string", encoded)
+	}
+
+	if again, _ := h.Hash("correct horse battery staple"); again == encoded {
+		t.Error("Hash() returned the same string twice: salt is not random")
+	}
+
+	ok, needsRehash, err := h.Verify("correct horse battery staple", encoded)
+	if !ok || needsRehash || err != nil {
+		t.Errorf("Verify(correct) = %v, %v, %v, want true, false, nil", ok, needsRehash, err)
+	}
+	ok, _, err = h.Verify("correct horse battery stapler", encoded)
+	if ok || err != nil {
+		t.Errorf("Verify(wrong) = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestVerifyNeedsRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret-password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weakArgon2 := newTestHasher(t, AlgorithmArgon2id, testArgon2Params, bcrypt.MinCost)
+	weakArgon2Hash, _ := weakArgon2.Hash("secret-password")
+
+	stronger := testArgon2Params
+	stronger.Iterations = 2
+
+	cases := []struct {
+		name        string
+		hasher      *Hasher
+		encoded     string
+		needsRehash bool
+	}{
+		{"legacy bcrypt under argon2id", newTestHasher(t, AlgorithmArgon2id, testArgon2Params, bcrypt.MinCost), string(legacy), true},
+		{"bcrypt with current cost", newTestHasher(t, AlgorithmBcrypt, testArgon2Params, bcrypt.MinCost), string(legacy), false},
+		{"bcrypt with old cost", newTestHasher(t, AlgorithmBcrypt, testArgon2Params, bcrypt.MinCost+1), string(legacy), true},
+		{"argon2id with current params", weakArgon2, weakArgon2Hash, false},
+		{"argon2id with old params", newTestHasher(t, AlgorithmArgon2id, stronger, bcrypt.MinCost), weakArgon2Hash, true},
+		{"argon2id under bcrypt", newTestHasher(t, AlgorithmBcrypt, testArgon2Params, bcrypt.MinCost), weakArgon2Hash, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ok, needsRehash, err := tc.hasher.Verify("secret-password", tc.encoded)
+			if !ok || err != nil {
+				t.Fatalf("Verify() = %v, %v, want true, nil", ok, err)
+			}
+			if needsRehash != tc.needsRehash {
+				t.Errorf("needsRehash = %v, want %v", needsRehash, tc.needsRehash)
+			}
+
+			// неверный пароль никогда не требует пересчёта
+			if ok, needsRehash, _ := tc.hasher.Verify("wrong-password", tc.encoded); ok || needsRehash {
+				t.Errorf("Verify(wrong) = %v, %v, want false, false", ok, needsRehash)
+			}
+		})
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	h := newTestHasher(t, AlgorithmArgon2id, testArgon2Params, bcrypt.MinCost)
+
+	cases := map[string]error{
+		"":                          ErrUnknownHash,
+		"plaintext":                 ErrUnknownHash,
+		"$scrypt$ln=16,r=8,p=1$a$b": ErrUnknownHash,
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0":        ErrMalformedHash,
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0$aGFzaA": ErrMalformedHash,
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdHNhbHRzYWx0$aGFzaA": ErrMalformedHash,
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$aGFzaA":              ErrMalformedHash,
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHRzYWx0$":       ErrMalformedHash,
+		"$2a$10$short": ErrMalformedHash,
+	}
+	for encoded, want := range cases {
+		ok, _, err := h.Verify("password", encoded)
+		if ok || !errors.Is(err, want) {
+			t.Errorf("Verify(%q) = %v, %v, want false, %v", encoded, ok, err, want)
+		}
+	}
+}
+
+func TestNewHasherRejectsBadConfig(t *testing.T) {
+	cases := []Config{
+		{Algorithm: "md5", Argon2: testArgon2Params, BcryptCost: bcrypt.MinCost},
+		{Algorithm: AlgorithmArgon2id, Argon2: Argon2Params{Memory: 1024, Iterations: 0, Parallelism: 1, SaltLength: 16, KeyLength: 32}, BcryptCost: bcrypt.MinCost},
+		{Algorithm: AlgorithmArgon2id, Argon2: testArgon2Params, BcryptCost: bcrypt.MaxCost + 1},
+	}
+	for _, cfg := range cases {
+		if _, err := NewHasher(cfg); err == nil {
+			t.Errorf("NewHasher(%+v) succeeded", cfg)
+		}
+	}
+}
+
+// go test -bench . -benchmem ./libs/1_domain_methods/passwords — время одного хэша с разными параметрами.
+// Для входа разумно 50–250 мс на хэш на боевом железе; память argon2id расходуется на каждый параллельный вход
+func BenchmarkArgon2id(b *testing.B) {
+	for _, params := range []Argon2Params{
+		{Memory: 19 * 1024, Iterations: 2, Parallelism: 1},
+		{Memory: 46 * 1024, Iterations: 1, Parallelism: 1},
+		{Memory: 64 * 1024, Iterations: 3, Parallelism: 4},
+		{Memory: 128 * 1024, Iterations: 3, Parallelism: 4},
+	} {
+		params.SaltLength, params.KeyLength = 16, 32
+		hasher, err := NewArgon2idHasher(params)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("m=%d,t=%d,p=%d", params.Memory, params.Iterations, params.Parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := hasher.Hash("correct horse battery staple"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkBcrypt(b *testing.B) {
+	for _, cost := range []int{bcrypt.DefaultCost, 11, 12, 13} {
+		hasher, err := NewBcryptHasher(cost)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("cost=%d", cost), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := hasher.Hash("correct horse battery staple"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package sessions
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DeviceNameHeader — имя устройства, которое клиент показывает пользователю в списке сессий
+	DeviceNameHeader = "X-Device-Name"
+
+	maxDeviceNameLength = 100
+	maxUserAgentLength  = 512
+)
+
+// Device — откуда выполнен вход
+type Device struct {
+	Name      string
+	UserAgent string
+}
+
+// DeviceFromRequest берёт имя устройства из X-Device-Name, а без него — описывает User-Agent
+func DeviceFromRequest(r *http.Request) Device {
+	userAgent := truncate(r.UserAgent(), maxUserAgentLength)
+
+	name := truncate(strings.TrimSpace(r.Header.Get(DeviceNameHeader)), maxDeviceNameLength)
+	if name == "" {
+		name = describeUserAgent(userAgent)
+	}
+	return Device{Name: name, UserAgent: userAgent}
+}
+
+// describeUserAgent делает из User-Agent подпись вроде "Chrome on Windows". Порядок проверок важен:
+// Edge и Opera притворяются Chrome, Chrome — Safari, Android — Linux
+func describeUserAgent(userAgent string) string {
+	browsers := []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	systems := []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"CrOS", "ChromeOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	}
+
+	var browser, system string
+	for _, b := range browsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, s := range systems {
+		if strings.Contains(userAgent, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	default:
+		return system
+	}
+}
+
+func truncate(s string, maxRunes int) string {
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	return string([]rune(s)[:maxRunes])
+}
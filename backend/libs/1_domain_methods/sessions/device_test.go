@@ -0,0 +1,40 @@
+package sessions
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDescribeUserAgent(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36":                         "Chrome on Windows",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36 Edg/131.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.1 Safari/605.1.15":                   "Safari on macOS",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 18_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.1 Mobile/15E148 Safari/604.1": "Safari on iOS",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:133.0) Gecko/20100101 Firefox/133.0":                                                                  "Firefox on Linux",
+		"curl/8.5.0": "curl",
+		"":           "",
+	}
+
+	for userAgent, want := range cases {
+		if got := describeUserAgent(userAgent); got != want {
+			t.Errorf("describeUserAgent(%q) = %q, want %q", userAgent, got, want)
+		}
+	}
+}
+
+func TestDeviceFromRequest(t *testing.T) {
+	r := httptest.NewRequest("POST", "/auth/login", nil)
+	r.Header.Set("User-Agent", "curl/8.5.0")
+
+	if device := DeviceFromRequest(r); device.Name != "curl" || device.UserAgent != "curl/8.5.0" {
+		t.Fatalf("DeviceFromRequest() = %+v, want name from User-Agent", device)
+	}
+
+	r.Header.Set(DeviceNameHeader, "  "+strings.Repeat("я", 150)+"  ")
+	if device := DeviceFromRequest(r); device.Name != strings.Repeat("я", maxDeviceNameLength) {
+		t.Fatalf("DeviceFromRequest() name = %q, want header truncated to %d runes", device.Name, maxDeviceNameLength)
+	}
+}
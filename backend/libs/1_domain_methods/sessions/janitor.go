@@ -0,0 +1,260 @@
+package sessions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/metrics"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+const (
+	JanitorModeDelete  = "delete"
+	JanitorModeArchive = "archive"
+
+	// janitorLockKey — ключ pg_try_advisory_lock, общий для всех инстансов: чистку ведёт кто-то один
+	janitorLockKey int64 = 0x72656672657368 // "refresh"
+)
+
+var ErrJanitorLocked = errors.New("refresh token cleanup is already running on another instance")
+
+type JanitorConfig struct {
+	// Interval — как часто backend-api запускает чистку, 0 — не запускает
+	Interval time.Duration
+	// Retention — сколько хранить использованные, отозванные и истёкшие токены. Использованные нужны,
+	// чтобы распознать повторное предъявление украденного токена
+	Retention time.Duration
+	BatchSize int
+	// Mode — delete или archive (перенос в refresh_tokens_archive)
+	Mode string
+}
+
+func LoadJanitorConfig(sctx smart_context.ISmartContext) JanitorConfig {
+	mode := strings.ToLower(os.Getenv("TOKEN_JANITOR_MODE"))
+	if mode == "" {
+		mode = JanitorModeDelete
+	}
+
+	return JanitorConfig{
+		Interval:  time.Duration(env_vars.GetEnvAsInt(sctx, "TOKEN_JANITOR_INTERVAL_SECONDS", 3600)) * time.Second,
+		Retention: time.Duration(env_vars.GetEnvAsInt(sctx, "TOKEN_JANITOR_RETENTION_SECONDS", 7*24*3600)) * time.Second,
+		BatchSize: env_vars.GetEnvAsInt(sctx, "TOKEN_JANITOR_BATCH_SIZE", 5000),
+		Mode:      mode,
+	}
+}
+
+func (cfg JanitorConfig) Validate() error {
+	if cfg.Mode != JanitorModeDelete && cfg.Mode != JanitorModeArchive {
+		return fmt.Errorf("unknown token janitor mode %q", cfg.Mode)
+	}
+	if cfg.BatchSize <= 0 {
+		return fmt.Errorf("invalid token janitor batch size %d", cfg.BatchSize)
+	}
+	return nil
+}
+
+type CleanupResult struct {
+	Mode string `json:"mode"`
+	// Rows — сколько токенов удалено или перенесено в архив
+	Rows int64 `json:"rows"`
+	// ExpiredRows — сколько удалено истёкших challenge, ссылок и кодов из expiringTables
+	ExpiredRows int64     `json:"expired_rows"`
+	Batches     int       `json:"batches"`
+	Before      time.Time `json:"before"`
+	DurationMs  int64     `json:"duration_ms"`
+}
+
+// staleTokensQuery выбирает пачку токенов, которые больше не понадобятся: использованные,
+// отозванные и истёкшие раньше $1. У токенов без expires_at срок считается по текущим настройкам,
+// как в CheckExpiry: $3, $4 и $5 — отметки из legacyExpiryCutoffs
+const staleTokensQuery = `SELECT id FROM refresh_tokens
+	WHERE (used = true AND created_at < $1) OR revoked_at < $1 OR expires_at < $1
+		OR (expires_at IS NULL AND (created_at < $3 OR last_used_at < $4 OR session_started_at < $5))
+	LIMIT $2`
+
+const deleteStaleTokensQuery = `DELETE FROM refresh_tokens WHERE id IN (` + staleTokensQuery + `)`
+
+const archiveStaleTokensQuery = `WITH moved AS (
+	DELETE FROM refresh_tokens WHERE id IN (` + staleTokensQuery + `)
+	RETURNING id, user_id, family_id, ip_address, user_agent, device_name, client_id, scope, amr,
+		used, created_at, last_used_at, revoked_at, session_started_at, expires_at
+)
+INSERT INTO refresh_tokens_archive (id, user_id, family_id, ip_address, user_agent, device_name, client_id, scope, amr,
+	used, created_at, last_used_at, revoked_at, session_started_at, expires_at)
+SELECT * FROM moved`
+
+// expiringTables — одноразовые challenge, ссылки и коды входа. После expires_at они уже не принимаются,
+// а вставить строку в часть из них может и анонимный запрос, поэтому их чистит тот же janitor
+var expiringTables = []string{
+	"authorization_codes",
+	"mfa_challenges",
+	"webauthn_challenges",
+	"password_reset_tokens",
+	"email_verification_tokens",
+	"magic_links",
+}
+
+// expiredRowsQuery удаляет из table пачку строк, истёкших раньше $1
+func expiredRowsQuery(table string) string {
+	return `DELETE FROM ` + table + ` WHERE id IN (SELECT id FROM ` + table + ` WHERE expires_at < $1 LIMIT $2)`
+}
+
+// StartJanitor запускает чистку refresh-токенов раз в cfg.Interval, пока не отменён контекст sctx
+func StartJanitor(sctx smart_context.ISmartContext, cfg JanitorConfig) {
+	if cfg.Interval <= 0 {
+		sctx.Info("Refresh token janitor is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			result, err := Cleanup(sctx, cfg)
+			switch {
+			case errors.Is(err, ErrJanitorLocked):
+				sctx.Debugf("Refresh token janitor skipped: %v", err)
+			case err != nil:
+				sctx.Errorf("refresh token janitor error: %v", err)
+			case result.Rows > 0 || result.ExpiredRows > 0:
+				sctx.Infof("Refresh token janitor: %s %d tokens and deleted %d expired challenges older than %s",
+					result.Mode, result.Rows, result.ExpiredRows, cfg.Retention)
+			}
+
+			select {
+			case <-sctx.GetContext().Done():
+				sctx.Info("Refresh token janitor stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Cleanup удаляет или архивирует пачками токены, ставшие ненужными раньше cfg.Retention,
+// и удаляет истёкшие раньше того же срока строки expiringTables.
+// Пачки — отдельные запросы, чтобы не держать долгих блокировок таблицы, через которую идут все входы
+func Cleanup(sctx smart_context.ISmartContext, cfg JanitorConfig) (*CleanupResult, error) {
+	result, err := cleanup(sctx, cfg)
+	switch {
+	case errors.Is(err, ErrJanitorLocked):
+		metrics.TokenJanitorRuns.Add("skipped", 1)
+	case err != nil:
+		metrics.TokenJanitorRuns.Add("failed", 1)
+	default:
+		metrics.TokenJanitorRuns.Add("completed", 1)
+		metrics.TokenJanitorLastSuccess.Set(time.Now().Unix())
+	}
+	if result != nil && result.Rows > 0 {
+		rowsMetric := "deleted"
+		if result.Mode == JanitorModeArchive {
+			rowsMetric = "archived"
+		}
+		metrics.TokenJanitorRows.Add(rowsMetric, result.Rows)
+	}
+	if result != nil && result.ExpiredRows > 0 {
+		metrics.TokenJanitorRows.Add("expired", result.ExpiredRows)
+	}
+	return result, err
+}
+
+func cleanup(sctx smart_context.ISmartContext, cfg JanitorConfig) (*CleanupResult, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	query := deleteStaleTokensQuery
+	if cfg.Mode == JanitorModeArchive {
+		query = archiveStaleTokensQuery
+	}
+
+	sqlDB, err := sctx.GetDB().DB()
+	if err != nil {
+		return nil, err
+	}
+
+	// session-level advisory lock живёт на соединении, поэтому все запросы идут через одно
+	ctx := sctx.GetContext()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", janitorLockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrJanitorLocked
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", janitorLockKey); err != nil {
+			sctx.Errorf("release token janitor lock error: %v", err)
+		}
+	}()
+
+	// refresh_tokens пишутся с локальным time.Now() в TIMESTAMP без зоны, поэтому и отметка в локальном времени:
+	// в UTC она сдвинулась бы на смещение зоны
+	started := time.Now()
+	result := &CleanupResult{Mode: cfg.Mode, Before: started.Add(-cfg.Retention)}
+	defer func() {
+		result.DurationMs = time.Since(started).Milliseconds()
+	}()
+
+	refreshCutoff, idleCutoff, sessionCutoff := legacyExpiryCutoffs(result.Before, lifetime)
+	rows, err := execBatches(ctx, conn, result, query, result.Before, cfg.BatchSize, refreshCutoff, idleCutoff, sessionCutoff)
+	result.Rows += rows
+	if err != nil {
+		return result, err
+	}
+
+	for _, table := range expiringTables {
+		rows, err := execBatches(ctx, conn, result, expiredRowsQuery(table), result.Before, cfg.BatchSize)
+		result.ExpiredRows += rows
+		if err != nil {
+			return result, fmt.Errorf("clean up %s: %w", table, err)
+		}
+	}
+	return result, nil
+}
+
+// execBatches повторяет запрос пачками по batchSize строк, пока пачка не окажется неполной.
+// Второй аргумент запроса ($2) — размер пачки
+func execBatches(ctx context.Context, conn *sql.Conn, result *CleanupResult, query string, before time.Time, batchSize int, args ...any) (int64, error) {
+	var total int64
+	for {
+		res, err := conn.ExecContext(ctx, query, append([]any{before, batchSize}, args...)...)
+		if err != nil {
+			return total, err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+
+		total += rows
+		result.Batches++
+		if rows < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// legacyExpiryCutoffs — для токенов, выданных без expires_at: токен истёк раньше before, если выпущен,
+// последний раз обновлён или начал сессию раньше соответствующей отметки. nil — срок не ограничен
+func legacyExpiryCutoffs(before time.Time, cfg Config) (refresh, idle, session *time.Time) {
+	cutoff := func(limit time.Duration) *time.Time {
+		if limit <= 0 {
+			return nil
+		}
+		at := before.Add(-limit)
+		return &at
+	}
+	return cutoff(cfg.RefreshTTL), cutoff(cfg.IdleTimeout), cutoff(cfg.MaxLifetime)
+}
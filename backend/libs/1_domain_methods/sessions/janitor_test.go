@@ -0,0 +1,45 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJanitorConfigValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		cfg     JanitorConfig
+		wantErr bool
+	}{
+		{"delete", JanitorConfig{Mode: JanitorModeDelete, BatchSize: 100}, false},
+		{"archive", JanitorConfig{Mode: JanitorModeArchive, BatchSize: 100}, false},
+		{"unknown mode", JanitorConfig{Mode: "truncate", BatchSize: 100}, true},
+		{"zero batch", JanitorConfig{Mode: JanitorModeDelete}, true},
+	}
+
+	for _, c := range cases {
+		if err := c.cfg.Validate(); (err != nil) != c.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", c.name, err, c.wantErr)
+		}
+	}
+}
+
+func TestLegacyExpiryCutoffs(t *testing.T) {
+	before := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	refresh, idle, session := legacyExpiryCutoffs(before, Config{RefreshTTL: 30 * 24 * time.Hour, IdleTimeout: 14 * 24 * time.Hour})
+	if refresh == nil || !refresh.Equal(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("refresh cutoff = %v, want 2030-01-01", refresh)
+	}
+	if idle == nil || !idle.Equal(time.Date(2030, 1, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("idle cutoff = %v, want 2030-01-17", idle)
+	}
+	// без предельного срока сессии по нему ничего не удаляется
+	if session != nil {
+		t.Errorf("session cutoff = %v, want nil", session)
+	}
+
+	if refresh, idle, session := legacyExpiryCutoffs(before, Config{}); refresh != nil || idle != nil || session != nil {
+		t.Errorf("cutoffs without limits = %v, %v, %v, want nil", refresh, idle, session)
+	}
+}
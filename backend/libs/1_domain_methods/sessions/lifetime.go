@@ -0,0 +1,94 @@
+package sessions
+
+import (
+	"errors"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+var (
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrSessionIdle         = errors.New("session expired due to inactivity")
+	ErrSessionTooOld       = errors.New("session lifetime exceeded")
+)
+
+// Config — сроки жизни сессии. Нулевой срок не ограничивает
+type Config struct {
+	// RefreshTTL — срок жизни одного refresh-токена
+	RefreshTTL time.Duration
+	// IdleTimeout — сессия завершается, если её столько времени не обновляли
+	IdleTimeout time.Duration
+	// MaxLifetime — предельный срок сессии от входа, обновления его не продлевают
+	MaxLifetime time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		RefreshTTL:  30 * 24 * time.Hour,
+		IdleTimeout: 14 * 24 * time.Hour,
+		MaxLifetime: 90 * 24 * time.Hour,
+	}
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	cfg := DefaultConfig()
+	cfg.RefreshTTL = time.Duration(env_vars.GetEnvAsInt(sctx, "REFRESH_TOKEN_TTL_SECONDS", int(cfg.RefreshTTL.Seconds()))) * time.Second
+	cfg.IdleTimeout = time.Duration(env_vars.GetEnvAsInt(sctx, "SESSION_IDLE_TIMEOUT_SECONDS", int(cfg.IdleTimeout.Seconds()))) * time.Second
+	cfg.MaxLifetime = time.Duration(env_vars.GetEnvAsInt(sctx, "SESSION_MAX_LIFETIME_SECONDS", int(cfg.MaxLifetime.Seconds()))) * time.Second
+	return cfg
+}
+
+var lifetime = DefaultConfig()
+
+// Configure задаёт сроки жизни сессий для всего процесса
+func Configure(cfg Config) {
+	lifetime = cfg
+}
+
+// ExpiresAt — когда истечёт refresh-токен, выпущенный в issuedAt в сессии, начатой в startedAt:
+// самый ранний из сроков. Ноль — сроки не ограничены
+func ExpiresAt(startedAt, issuedAt time.Time) time.Time {
+	var expiresAt time.Time
+	earliest := func(limit time.Duration, from time.Time) {
+		if limit <= 0 {
+			return
+		}
+		if at := from.Add(limit); expiresAt.IsZero() || at.Before(expiresAt) {
+			expiresAt = at
+		}
+	}
+
+	earliest(lifetime.RefreshTTL, issuedAt)
+	earliest(lifetime.IdleTimeout, issuedAt)
+	earliest(lifetime.MaxLifetime, startedAt)
+	return expiresAt
+}
+
+// TokenExpiresAt — когда истечёт выданный refresh-токен: по записанному сроку или текущим настройкам,
+// что раньше. Ноль — без срока
+func TokenExpiresAt(token *model.RefreshToken) time.Time {
+	expiresAt := ExpiresAt(token.SessionStartedAt, token.LastUsedAt)
+	// last_used_at — момент выпуска строки: каждое обновление выпускает новую
+	if !token.ExpiresAt.IsZero() && (expiresAt.IsZero() || token.ExpiresAt.Before(expiresAt)) {
+		expiresAt = token.ExpiresAt
+	}
+	return expiresAt
+}
+
+// CheckExpiry проверяет сроки refresh-токена: записанный при выпуске и текущие настройки —
+// так укороченные сроки действуют и на уже выданные токены, в том числе выданные без expires_at
+func CheckExpiry(token *model.RefreshToken, now time.Time) error {
+	switch {
+	case lifetime.MaxLifetime > 0 && now.After(token.SessionStartedAt.Add(lifetime.MaxLifetime)):
+		return ErrSessionTooOld
+	case lifetime.IdleTimeout > 0 && now.After(token.LastUsedAt.Add(lifetime.IdleTimeout)):
+		return ErrSessionIdle
+	case lifetime.RefreshTTL > 0 && now.After(token.CreatedAt.Add(lifetime.RefreshTTL)):
+		return ErrRefreshTokenExpired
+	case !token.ExpiresAt.IsZero() && now.After(token.ExpiresAt):
+		return ErrRefreshTokenExpired
+	}
+	return nil
+}
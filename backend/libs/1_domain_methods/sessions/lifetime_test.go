@@ -0,0 +1,69 @@
+package sessions
+
+import (
+	"errors"
+	"test-task3/libs/2_generated_models/model"
+	"testing"
+	"time"
+)
+
+func TestExpiresAt(t *testing.T) {
+	Configure(Config{RefreshTTL: 30 * 24 * time.Hour, IdleTimeout: 14 * 24 * time.Hour, MaxLifetime: 90 * 24 * time.Hour})
+	defer Configure(DefaultConfig())
+
+	started := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, want := ExpiresAt(started, started), started.Add(14*24*time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt(login) = %s, want idle timeout %s", got, want)
+	}
+
+	// обновление за 5 дней до предельного срока не продлевает сессию дальше него
+	issued := started.Add(85 * 24 * time.Hour)
+	if got, want := ExpiresAt(started, issued), started.Add(90*24*time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt(late refresh) = %s, want max lifetime %s", got, want)
+	}
+
+	Configure(Config{})
+	if got := ExpiresAt(started, issued); !got.IsZero() {
+		t.Errorf("ExpiresAt(no limits) = %s, want zero", got)
+	}
+}
+
+func TestCheckExpiry(t *testing.T) {
+	Configure(Config{RefreshTTL: 30 * 24 * time.Hour, IdleTimeout: 14 * 24 * time.Hour, MaxLifetime: 90 * 24 * time.Hour})
+	defer Configure(DefaultConfig())
+
+	started := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	issued := started.Add(80 * 24 * time.Hour)
+	token := &model.RefreshToken{
+		CreatedAt:        issued,
+		LastUsedAt:       issued,
+		SessionStartedAt: started,
+		ExpiresAt:        ExpiresAt(started, issued),
+	}
+
+	cases := []struct {
+		now  time.Time
+		want error
+	}{
+		{issued.Add(time.Hour), nil},
+		{issued.Add(9 * 24 * time.Hour), nil},
+		{issued.Add(10*24*time.Hour + time.Hour), ErrSessionTooOld},
+	}
+	for _, c := range cases {
+		if err := CheckExpiry(token, c.now); !errors.Is(err, c.want) {
+			t.Errorf("CheckExpiry(now=%s) = %v, want %v", c.now, err, c.want)
+		}
+	}
+
+	token.SessionStartedAt = issued
+	if err := CheckExpiry(token, issued.Add(15*24*time.Hour)); !errors.Is(err, ErrSessionIdle) {
+		t.Errorf("CheckExpiry(idle) = %v, want %v", err, ErrSessionIdle)
+	}
+
+	// записанный при выпуске срок действует, даже если настройки потом ослабили
+	Configure(Config{})
+	if err := CheckExpiry(token, token.ExpiresAt.Add(time.Second)); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Errorf("CheckExpiry(stored expiry) = %v, want %v", err, ErrRefreshTokenExpired)
+	}
+}
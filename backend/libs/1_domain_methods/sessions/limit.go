@@ -0,0 +1,156 @@
+package sessions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"test-task3/libs/1_domain_methods/access"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+type LimitPolicy string
+
+const (
+	// LimitRevokeOldest — новый вход завершает давно не использованные сессии
+	LimitRevokeOldest LimitPolicy = "revoke_oldest"
+	// LimitReject — новый вход отклоняется, пока пользователь сам не завершит одну из сессий
+	LimitReject LimitPolicy = "reject"
+)
+
+var ErrSessionLimitReached = errors.New("active session limit reached")
+
+// LimitConfig — лимит активных сессий. У ролей и пользователей он переопределяется полем max_sessions
+type LimitConfig struct {
+	// MaxSessions — лимит по умолчанию, 0 — без ограничения
+	MaxSessions int
+	Policy      LimitPolicy
+}
+
+func ParseLimitPolicy(value string) (LimitPolicy, error) {
+	switch policy := LimitPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return LimitRevokeOldest, nil
+	case LimitRevokeOldest, LimitReject:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown session limit policy %q", value)
+	}
+}
+
+func LoadLimitConfig(sctx smart_context.ISmartContext) (LimitConfig, error) {
+	policy, err := ParseLimitPolicy(os.Getenv("SESSION_LIMIT_POLICY"))
+	if err != nil {
+		return LimitConfig{}, err
+	}
+	return LimitConfig{
+		MaxSessions: env_vars.GetEnvAsInt(sctx, "SESSION_LIMIT", 0),
+		Policy:      policy,
+	}, nil
+}
+
+var limits = LimitConfig{Policy: LimitRevokeOldest}
+
+// ConfigureLimits задаёт лимит сессий для всего процесса
+func ConfigureLimits(cfg LimitConfig) {
+	limits = cfg
+}
+
+// MaxSessions — лимит сессий пользователя: собственный, иначе самый мягкий из лимитов ролей, иначе общий.
+// 0 — без ограничения
+func MaxSessions(sctx smart_context.ISmartContext, userId string) (int, error) {
+	var user model.User
+	if err := sctx.GetDB().Select("max_sessions").Where("id = ?", userId).Limit(1).Find(&user).Error; err != nil {
+		return 0, err
+	}
+
+	var roleLimits []int
+	err := sctx.GetDB().Model(&model.Role{}).
+		Joins("JOIN user_roles ON user_roles.role = roles.name").
+		Where("user_roles.user_id = ? AND roles.max_sessions <> 0", userId).
+		Pluck("roles.max_sessions", &roleLimits).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return resolveLimit(int(user.MaxSessions), roleLimits, limits.MaxSessions), nil
+}
+
+// resolveLimit сводит лимиты: положительный — число сессий, отрицательный — без ограничения, 0 — не задан
+func resolveLimit(userLimit int, roleLimits []int, defaultLimit int) int {
+	if userLimit != 0 {
+		return max(userLimit, 0)
+	}
+
+	resolved := 0
+	for _, limit := range roleLimits {
+		if limit < 0 {
+			return 0
+		}
+		resolved = max(resolved, limit)
+	}
+	if resolved > 0 {
+		return resolved
+	}
+	return max(defaultLimit, 0)
+}
+
+// Admit освобождает место под новую сессию пользователя. При LimitReject возвращает ErrSessionLimitReached,
+// при LimitRevokeOldest завершает давно не использованные сессии и возвращает их id.
+// Вызывается в транзакции вместе с выпуском токена: строка пользователя блокируется, чтобы параллельные
+// входы не превысили лимит
+func Admit(sctx smart_context.ISmartContext, userId string) ([]string, error) {
+	limit, err := MaxSessions(sctx, userId)
+	if err != nil || limit == 0 {
+		return nil, err
+	}
+
+	err = sctx.GetDB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", userId).Limit(1).Find(&model.User{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := List(sctx, userId, "")
+	if err != nil {
+		return nil, err
+	}
+	excess := len(active) - limit + 1
+	if excess <= 0 {
+		return nil, nil
+	}
+	if limits.Policy == LimitReject {
+		return nil, ErrSessionLimitReached
+	}
+
+	// List отдаёт недавно использованные первыми
+	revoked := make([]string, 0, excess)
+	for _, session := range active[len(active)-excess:] {
+		revoked = append(revoked, session.ID)
+	}
+
+	err = activeTokens(sctx, userId).
+		Where("family_id IN ?", revoked).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// SetUserMaxSessions задаёт пользователю собственный лимит сессий: 0 — как у ролей, отрицательный — без ограничения
+func SetUserMaxSessions(sctx smart_context.ISmartContext, userId string, maxSessions int) error {
+	result := sctx.GetDB().Model(&model.User{}).Where("id = ?", userId).Update("max_sessions", maxSessions)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return access.ErrUserNotFound
+	}
+	return nil
+}
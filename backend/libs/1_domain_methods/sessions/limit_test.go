@@ -0,0 +1,45 @@
+package sessions
+
+import "testing"
+
+func TestResolveLimit(t *testing.T) {
+	cases := []struct {
+		name         string
+		userLimit    int
+		roleLimits   []int
+		defaultLimit int
+		want         int
+	}{
+		{"default", 0, nil, 5, 5},
+		{"no limit by default", 0, nil, 0, 0},
+		{"role overrides default", 0, []int{10}, 5, 10},
+		{"most permissive role wins", 0, []int{2, 10, 3}, 5, 10},
+		{"unlimited role wins", 0, []int{2, -1}, 5, 0},
+		{"user overrides roles", 1, []int{10}, 5, 1},
+		{"unlimited user", -1, []int{2}, 5, 0},
+		{"negative default", 0, nil, -3, 0},
+	}
+
+	for _, c := range cases {
+		if got := resolveLimit(c.userLimit, c.roleLimits, c.defaultLimit); got != c.want {
+			t.Errorf("%s: resolveLimit(%d, %v, %d) = %d, want %d", c.name, c.userLimit, c.roleLimits, c.defaultLimit, got, c.want)
+		}
+	}
+}
+
+func TestParseLimitPolicy(t *testing.T) {
+	cases := map[string]LimitPolicy{
+		"":              LimitRevokeOldest,
+		"revoke_oldest": LimitRevokeOldest,
+		" Reject ":      LimitReject,
+	}
+	for value, want := range cases {
+		if got, err := ParseLimitPolicy(value); err != nil || got != want {
+			t.Errorf("ParseLimitPolicy(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+
+	if _, err := ParseLimitPolicy("revoke_newest"); err == nil {
+		t.Error("ParseLimitPolicy(revoke_newest) succeeded, want error")
+	}
+}
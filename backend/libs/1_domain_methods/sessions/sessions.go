@@ -0,0 +1,89 @@
+package sessions
+
+import (
+	"errors"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session — вход с одного устройства: цепочка refresh-токенов с общим family_id.
+// Адрес, User-Agent и время — с последнего обновления токенов
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	ClientID   string    `json:"client_id,omitempty"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	StartedAt  time.Time `json:"started_at"`
+	// ExpiresAt — когда сессия завершится, если её не обновлять. nil — без срока
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Current — сессия, которой выпущен access-токен запроса
+	Current bool `json:"current"`
+}
+
+// List возвращает активные сессии пользователя, недавно использованные первыми
+func List(sctx smart_context.ISmartContext, userId, currentSessionId string) ([]Session, error) {
+	var tokens []model.RefreshToken
+	err := activeTokens(sctx, userId).Order("last_used_at DESC").Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		if CheckExpiry(&token, now) != nil {
+			continue
+		}
+		session := Session{
+			ID:         token.FamilyID,
+			DeviceName: token.DeviceName,
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			ClientID:   token.ClientID,
+			LastUsedAt: token.LastUsedAt,
+			StartedAt:  token.SessionStartedAt,
+			Current:    token.FamilyID == currentSessionId,
+		}
+		if expiresAt := TokenExpiresAt(&token); !expiresAt.IsZero() {
+			session.ExpiresAt = &expiresAt
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// Revoke завершает сессию пользователя: её refresh-токен больше не обновляется.
+// Выданный сессией access-токен действует до своего истечения
+func Revoke(sctx smart_context.ISmartContext, userId, sessionId string) error {
+	result := activeTokens(sctx, userId).
+		Where("family_id = ?", sessionId).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOthers завершает все сессии пользователя, кроме текущей, и возвращает их число
+func RevokeOthers(sctx smart_context.ISmartContext, userId, currentSessionId string) (int64, error) {
+	result := activeTokens(sctx, userId).
+		Where("family_id <> ?", currentSessionId).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// activeTokens — последние refresh-токены действующих сессий пользователя, по одному на сессию
+func activeTokens(sctx smart_context.ISmartContext, userId string) *gorm.DB {
+	return sctx.GetDB().Model(&model.RefreshToken{}).
+		Where("user_id = ? AND used = false AND revoked_at IS NULL", userId)
+}
@@ -0,0 +1,128 @@
+package sessions
+
+import (
+	"errors"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/test_db"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// newSessionsContext создаёт сессии alice: a (использована минуту назад, у неё есть и прошлый, уже использованный
+// токен), b (пять минут назад), отозванную c и истёкшую d. У bob одна сессия x
+func newSessionsContext(t *testing.T) (smart_context.ISmartContext, *gorm.DB) {
+	t.Helper()
+
+	db := test_db.Open(t, "refresh_tokens")
+	now := time.Now()
+	tokens := []model.RefreshToken{
+		{UserID: "alice", FamilyID: "a", Used: true, LastUsedAt: now.Add(-time.Hour)},
+		{UserID: "alice", FamilyID: "a", DeviceName: "laptop", LastUsedAt: now.Add(-time.Minute)},
+		{UserID: "alice", FamilyID: "b", DeviceName: "phone", LastUsedAt: now.Add(-5 * time.Minute)},
+		{UserID: "alice", FamilyID: "c", LastUsedAt: now, RevokedAt: now.Add(-time.Second)},
+		{UserID: "alice", FamilyID: "d", LastUsedAt: now, ExpiresAt: now.Add(-time.Second)},
+		{UserID: "bob", FamilyID: "x", LastUsedAt: now},
+	}
+	for i := range tokens {
+		tokens[i].HashedToken = "hash"
+		tokens[i].IPAddress = "203.0.113.7"
+		tokens[i].SessionStartedAt = now.Add(-2 * time.Hour)
+
+		var omit []string
+		if tokens[i].RevokedAt.IsZero() {
+			omit = append(omit, "RevokedAt")
+		}
+		if tokens[i].ExpiresAt.IsZero() {
+			omit = append(omit, "ExpiresAt")
+		}
+		if err := db.Omit(omit...).Create(&tokens[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return smart_context.NewSmartContext().WithDB(db), db
+}
+
+func sessionIDs(t *testing.T, sctx smart_context.ISmartContext, userId, currentSessionId string) []string {
+	t.Helper()
+
+	list, err := List(sctx, userId, currentSessionId)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, session := range list {
+		ids = append(ids, session.ID)
+	}
+	return ids
+}
+
+func TestList(t *testing.T) {
+	sctx, _ := newSessionsContext(t)
+
+	list, err := List(sctx, "alice", "b")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	// отозванная и истёкшая сессии не показываются, по одной записи на сессию
+	if len(list) != 2 || list[0].ID != "a" || list[1].ID != "b" {
+		t.Fatalf("List() = %+v, want sessions a and b", list)
+	}
+	if list[0].Current || !list[1].Current {
+		t.Errorf("current flags = %v, %v, want only b", list[0].Current, list[1].Current)
+	}
+	if list[0].DeviceName != "laptop" {
+		t.Errorf("device of a = %q, want the latest token's laptop", list[0].DeviceName)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	sctx, _ := newSessionsContext(t)
+
+	// чужая сессия выглядит как несуществующая
+	if err := Revoke(sctx, "alice", "x"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Revoke(another user's session) error = %v, want %v", err, ErrSessionNotFound)
+	}
+	if ids := sessionIDs(t, sctx, "bob", ""); len(ids) != 1 {
+		t.Errorf("bob sessions = %v, want x untouched", ids)
+	}
+
+	if err := Revoke(sctx, "alice", "b"); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if ids := sessionIDs(t, sctx, "alice", ""); len(ids) != 1 || ids[0] != "a" {
+		t.Errorf("alice sessions = %v, want [a]", ids)
+	}
+	if err := Revoke(sctx, "alice", "b"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("second Revoke() error = %v, want %v", err, ErrSessionNotFound)
+	}
+	if err := Revoke(sctx, "alice", "c"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Revoke(revoked session) error = %v, want %v", err, ErrSessionNotFound)
+	}
+}
+
+func TestRevokeOthers(t *testing.T) {
+	sctx, db := newSessionsContext(t)
+
+	// b и истёкшая d; использованный токен текущей сессии a не трогается
+	revoked, err := RevokeOthers(sctx, "alice", "a")
+	if err != nil || revoked != 2 {
+		t.Fatalf("RevokeOthers() = %d, %v, want 2", revoked, err)
+	}
+	if ids := sessionIDs(t, sctx, "alice", "a"); len(ids) != 1 || ids[0] != "a" {
+		t.Errorf("alice sessions = %v, want [a]", ids)
+	}
+
+	var active int64
+	if err := db.Model(&model.RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", "a").Count(&active).Error; err != nil {
+		t.Fatal(err)
+	}
+	if active != 2 {
+		t.Errorf("%d tokens of the current session left unrevoked, want 2", active)
+	}
+	if ids := sessionIDs(t, sctx, "bob", ""); len(ids) != 1 {
+		t.Errorf("bob sessions = %v, want x untouched", ids)
+	}
+}
@@ -12,12 +12,22 @@ const TableNameRefreshToken = "refresh_tokens"
 
 // RefreshToken mapped from table <refresh_tokens>
 type RefreshToken struct {
-	ID          string    `gorm:"column:id;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID      string    `gorm:"column:user_id;not null" json:"user_id"`
-	HashedToken string    `gorm:"column:hashed_token;not null" json:"hashed_token"`
-	IPAddress   string    `gorm:"column:ip_address;not null" json:"ip_address"`
-	Used        bool      `gorm:"column:used" json:"used"`
-	CreatedAt   time.Time `gorm:"column:created_at;default:now()" json:"created_at"`
+	ID               string    `gorm:"column:id;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID           string    `gorm:"column:user_id;not null" json:"user_id"`
+	HashedToken      string    `gorm:"column:hashed_token;not null" json:"hashed_token"`
+	IPAddress        string    `gorm:"column:ip_address;not null" json:"ip_address"`
+	Used             bool      `gorm:"column:used" json:"used"`
+	CreatedAt        time.Time `gorm:"column:created_at;default:now()" json:"created_at"`
+	ClientID         string    `gorm:"column:client_id;not null" json:"client_id"`
+	Scope            string    `gorm:"column:scope;not null" json:"scope"`
+	Amr              string    `gorm:"column:amr;not null" json:"amr"`
+	FamilyID         string    `gorm:"column:family_id;not null" json:"family_id"`
+	UserAgent        string    `gorm:"column:user_agent;not null" json:"user_agent"`
+	DeviceName       string    `gorm:"column:device_name;not null" json:"device_name"`
+	LastUsedAt       time.Time `gorm:"column:last_used_at;not null;default:now()" json:"last_used_at"`
+	RevokedAt        time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	SessionStartedAt time.Time `gorm:"column:session_started_at;not null;default:now()" json:"session_started_at"`
+	ExpiresAt        time.Time `gorm:"column:expires_at" json:"expires_at"`
 }
 
 // TableName RefreshToken's table name
@@ -12,11 +12,13 @@ const TableNameUser = "users"
 
 // User mapped from table <users>
 type User struct {
-	ID        string    `gorm:"column:id;primaryKey;default:gen_random_uuid()" json:"id"`
-	Email     string    `gorm:"column:email;not null" json:"email"`
-	Password  string    `gorm:"column:password;not null" json:"password"`
-	CreatedAt time.Time `gorm:"column:created_at;default:now()" json:"created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+	ID              string    `gorm:"column:id;primaryKey;default:gen_random_uuid()" json:"id"`
+	Email           string    `gorm:"column:email;not null" json:"email"`
+	Password        string    `gorm:"column:password;not null" json:"password"`
+	CreatedAt       time.Time `gorm:"column:created_at;default:now()" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"column:updated_at" json:"updated_at"`
+	EmailVerifiedAt time.Time `gorm:"column:email_verified_at" json:"email_verified_at"`
+	MaxSessions     int32     `gorm:"column:max_sessions;not null" json:"max_sessions"`
 }
 
 // TableName User's table name
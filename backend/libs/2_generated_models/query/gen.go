@@ -16,39 +16,139 @@ import (
 )
 
 var (
-	Q            = new(Query)
-	RefreshToken *refreshToken
-	User         *user
+	Q                      = new(Query)
+	APIKey                 *aPIKey
+	AuditEvent             *auditEvent
+	AuthorizationCode      *authorizationCode
+	Client                 *client
+	EmailVerificationToken *emailVerificationToken
+	LoginLockout           *loginLockout
+	MagicLink              *magicLink
+	MfaChallenge           *mfaChallenge
+	MfaRecoveryCode        *mfaRecoveryCode
+	PasswordResetToken     *passwordResetToken
+	Permission             *permission
+	RateLimitBucket        *rateLimitBucket
+	RefreshToken           *refreshToken
+	RevokedToken           *revokedToken
+	Role                   *role
+	RolePermission         *rolePermission
+	User                   *user
+	UserRole               *userRole
+	UserTokenRevocation    *userTokenRevocation
+	UserTotp               *userTotp
+	WebauthnChallenge      *webauthnChallenge
+	WebauthnCredential     *webauthnCredential
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
+	APIKey = &Q.APIKey
+	AuditEvent = &Q.AuditEvent
+	AuthorizationCode = &Q.AuthorizationCode
+	Client = &Q.Client
+	EmailVerificationToken = &Q.EmailVerificationToken
+	LoginLockout = &Q.LoginLockout
+	MagicLink = &Q.MagicLink
+	MfaChallenge = &Q.MfaChallenge
+	MfaRecoveryCode = &Q.MfaRecoveryCode
+	PasswordResetToken = &Q.PasswordResetToken
+	Permission = &Q.Permission
+	RateLimitBucket = &Q.RateLimitBucket
 	RefreshToken = &Q.RefreshToken
+	RevokedToken = &Q.RevokedToken
+	Role = &Q.Role
+	RolePermission = &Q.RolePermission
 	User = &Q.User
+	UserRole = &Q.UserRole
+	UserTokenRevocation = &Q.UserTokenRevocation
+	UserTotp = &Q.UserTotp
+	WebauthnChallenge = &Q.WebauthnChallenge
+	WebauthnCredential = &Q.WebauthnCredential
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:           db,
-		RefreshToken: newRefreshToken(db, opts...),
-		User:         newUser(db, opts...),
+		db:                     db,
+		APIKey:                 newAPIKey(db, opts...),
+		AuditEvent:             newAuditEvent(db, opts...),
+		AuthorizationCode:      newAuthorizationCode(db, opts...),
+		Client:                 newClient(db, opts...),
+		EmailVerificationToken: newEmailVerificationToken(db, opts...),
+		LoginLockout:           newLoginLockout(db, opts...),
+		MagicLink:              newMagicLink(db, opts...),
+		MfaChallenge:           newMfaChallenge(db, opts...),
+		MfaRecoveryCode:        newMfaRecoveryCode(db, opts...),
+		PasswordResetToken:     newPasswordResetToken(db, opts...),
+		Permission:             newPermission(db, opts...),
+		RateLimitBucket:        newRateLimitBucket(db, opts...),
+		RefreshToken:           newRefreshToken(db, opts...),
+		RevokedToken:           newRevokedToken(db, opts...),
+		Role:                   newRole(db, opts...),
+		RolePermission:         newRolePermission(db, opts...),
+		User:                   newUser(db, opts...),
+		UserRole:               newUserRole(db, opts...),
+		UserTokenRevocation:    newUserTokenRevocation(db, opts...),
+		UserTotp:               newUserTotp(db, opts...),
+		WebauthnChallenge:      newWebauthnChallenge(db, opts...),
+		WebauthnCredential:     newWebauthnCredential(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	RefreshToken refreshToken
-	User         user
+	APIKey                 aPIKey
+	AuditEvent             auditEvent
+	AuthorizationCode      authorizationCode
+	Client                 client
+	EmailVerificationToken emailVerificationToken
+	LoginLockout           loginLockout
+	MagicLink              magicLink
+	MfaChallenge           mfaChallenge
+	MfaRecoveryCode        mfaRecoveryCode
+	PasswordResetToken     passwordResetToken
+	Permission             permission
+	RateLimitBucket        rateLimitBucket
+	RefreshToken           refreshToken
+	RevokedToken           revokedToken
+	Role                   role
+	RolePermission         rolePermission
+	User                   user
+	UserRole               userRole
+	UserTokenRevocation    userTokenRevocation
+	UserTotp               userTotp
+	WebauthnChallenge      webauthnChallenge
+	WebauthnCredential     webauthnCredential
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:           db,
-		RefreshToken: q.RefreshToken.clone(db),
-		User:         q.User.clone(db),
+		db:                     db,
+		APIKey:                 q.APIKey.clone(db),
+		AuditEvent:             q.AuditEvent.clone(db),
+		AuthorizationCode:      q.AuthorizationCode.clone(db),
+		Client:                 q.Client.clone(db),
+		EmailVerificationToken: q.EmailVerificationToken.clone(db),
+		LoginLockout:           q.LoginLockout.clone(db),
+		MagicLink:              q.MagicLink.clone(db),
+		MfaChallenge:           q.MfaChallenge.clone(db),
+		MfaRecoveryCode:        q.MfaRecoveryCode.clone(db),
+		PasswordResetToken:     q.PasswordResetToken.clone(db),
+		Permission:             q.Permission.clone(db),
+		RateLimitBucket:        q.RateLimitBucket.clone(db),
+		RefreshToken:           q.RefreshToken.clone(db),
+		RevokedToken:           q.RevokedToken.clone(db),
+		Role:                   q.Role.clone(db),
+		RolePermission:         q.RolePermission.clone(db),
+		User:                   q.User.clone(db),
+		UserRole:               q.UserRole.clone(db),
+		UserTokenRevocation:    q.UserTokenRevocation.clone(db),
+		UserTotp:               q.UserTotp.clone(db),
+		WebauthnChallenge:      q.WebauthnChallenge.clone(db),
+		WebauthnCredential:     q.WebauthnCredential.clone(db),
 	}
 }
 
@@ -62,21 +162,81 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:           db,
-		RefreshToken: q.RefreshToken.replaceDB(db),
-		User:         q.User.replaceDB(db),
+		db:                     db,
+		APIKey:                 q.APIKey.replaceDB(db),
+		AuditEvent:             q.AuditEvent.replaceDB(db),
+		AuthorizationCode:      q.AuthorizationCode.replaceDB(db),
+		Client:                 q.Client.replaceDB(db),
+		EmailVerificationToken: q.EmailVerificationToken.replaceDB(db),
+		LoginLockout:           q.LoginLockout.replaceDB(db),
+		MagicLink:              q.MagicLink.replaceDB(db),
+		MfaChallenge:           q.MfaChallenge.replaceDB(db),
+		MfaRecoveryCode:        q.MfaRecoveryCode.replaceDB(db),
+		PasswordResetToken:     q.PasswordResetToken.replaceDB(db),
+		Permission:             q.Permission.replaceDB(db),
+		RateLimitBucket:        q.RateLimitBucket.replaceDB(db),
+		RefreshToken:           q.RefreshToken.replaceDB(db),
+		RevokedToken:           q.RevokedToken.replaceDB(db),
+		Role:                   q.Role.replaceDB(db),
+		RolePermission:         q.RolePermission.replaceDB(db),
+		User:                   q.User.replaceDB(db),
+		UserRole:               q.UserRole.replaceDB(db),
+		UserTokenRevocation:    q.UserTokenRevocation.replaceDB(db),
+		UserTotp:               q.UserTotp.replaceDB(db),
+		WebauthnChallenge:      q.WebauthnChallenge.replaceDB(db),
+		WebauthnCredential:     q.WebauthnCredential.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	RefreshToken IRefreshTokenDo
-	User         IUserDo
+	APIKey                 IAPIKeyDo
+	AuditEvent             IAuditEventDo
+	AuthorizationCode      IAuthorizationCodeDo
+	Client                 IClientDo
+	EmailVerificationToken IEmailVerificationTokenDo
+	LoginLockout           ILoginLockoutDo
+	MagicLink              IMagicLinkDo
+	MfaChallenge           IMfaChallengeDo
+	MfaRecoveryCode        IMfaRecoveryCodeDo
+	PasswordResetToken     IPasswordResetTokenDo
+	Permission             IPermissionDo
+	RateLimitBucket        IRateLimitBucketDo
+	RefreshToken           IRefreshTokenDo
+	RevokedToken           IRevokedTokenDo
+	Role                   IRoleDo
+	RolePermission         IRolePermissionDo
+	User                   IUserDo
+	UserRole               IUserRoleDo
+	UserTokenRevocation    IUserTokenRevocationDo
+	UserTotp               IUserTotpDo
+	WebauthnChallenge      IWebauthnChallengeDo
+	WebauthnCredential     IWebauthnCredentialDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		RefreshToken: q.RefreshToken.WithContext(ctx),
-		User:         q.User.WithContext(ctx),
+		APIKey:                 q.APIKey.WithContext(ctx),
+		AuditEvent:             q.AuditEvent.WithContext(ctx),
+		AuthorizationCode:      q.AuthorizationCode.WithContext(ctx),
+		Client:                 q.Client.WithContext(ctx),
+		EmailVerificationToken: q.EmailVerificationToken.WithContext(ctx),
+		LoginLockout:           q.LoginLockout.WithContext(ctx),
+		MagicLink:              q.MagicLink.WithContext(ctx),
+		MfaChallenge:           q.MfaChallenge.WithContext(ctx),
+		MfaRecoveryCode:        q.MfaRecoveryCode.WithContext(ctx),
+		PasswordResetToken:     q.PasswordResetToken.WithContext(ctx),
+		Permission:             q.Permission.WithContext(ctx),
+		RateLimitBucket:        q.RateLimitBucket.WithContext(ctx),
+		RefreshToken:           q.RefreshToken.WithContext(ctx),
+		RevokedToken:           q.RevokedToken.WithContext(ctx),
+		Role:                   q.Role.WithContext(ctx),
+		RolePermission:         q.RolePermission.WithContext(ctx),
+		User:                   q.User.WithContext(ctx),
+		UserRole:               q.UserRole.WithContext(ctx),
+		UserTokenRevocation:    q.UserTokenRevocation.WithContext(ctx),
+		UserTotp:               q.UserTotp.WithContext(ctx),
+		WebauthnChallenge:      q.WebauthnChallenge.WithContext(ctx),
+		WebauthnCredential:     q.WebauthnCredential.WithContext(ctx),
 	}
 }
 
@@ -33,6 +33,16 @@ func newRefreshToken(db *gorm.DB, opts ...gen.DOOption) refreshToken {
 	_refreshToken.IPAddress = field.NewString(tableName, "ip_address")
 	_refreshToken.Used = field.NewBool(tableName, "used")
 	_refreshToken.CreatedAt = field.NewTime(tableName, "created_at")
+	_refreshToken.ClientID = field.NewString(tableName, "client_id")
+	_refreshToken.Scope = field.NewString(tableName, "scope")
+	_refreshToken.Amr = field.NewString(tableName, "amr")
+	_refreshToken.FamilyID = field.NewString(tableName, "family_id")
+	_refreshToken.UserAgent = field.NewString(tableName, "user_agent")
+	_refreshToken.DeviceName = field.NewString(tableName, "device_name")
+	_refreshToken.LastUsedAt = field.NewTime(tableName, "last_used_at")
+	_refreshToken.RevokedAt = field.NewTime(tableName, "revoked_at")
+	_refreshToken.SessionStartedAt = field.NewTime(tableName, "session_started_at")
+	_refreshToken.ExpiresAt = field.NewTime(tableName, "expires_at")
 
 	_refreshToken.fillFieldMap()
 
@@ -42,13 +52,23 @@ func newRefreshToken(db *gorm.DB, opts ...gen.DOOption) refreshToken {
 type refreshToken struct {
 	refreshTokenDo
 
-	ALL         field.Asterisk
-	ID          field.String
-	UserID      field.String
-	HashedToken field.String
-	IPAddress   field.String
-	Used        field.Bool
-	CreatedAt   field.Time
+	ALL              field.Asterisk
+	ID               field.String
+	UserID           field.String
+	HashedToken      field.String
+	IPAddress        field.String
+	Used             field.Bool
+	CreatedAt        field.Time
+	ClientID         field.String
+	Scope            field.String
+	Amr              field.String
+	FamilyID         field.String
+	UserAgent        field.String
+	DeviceName       field.String
+	LastUsedAt       field.Time
+	RevokedAt        field.Time
+	SessionStartedAt field.Time
+	ExpiresAt        field.Time
 
 	fieldMap map[string]field.Expr
 }
@@ -71,6 +91,16 @@ func (r *refreshToken) updateTableName(table string) *refreshToken {
 	r.IPAddress = field.NewString(table, "ip_address")
 	r.Used = field.NewBool(table, "used")
 	r.CreatedAt = field.NewTime(table, "created_at")
+	r.ClientID = field.NewString(table, "client_id")
+	r.Scope = field.NewString(table, "scope")
+	r.Amr = field.NewString(table, "amr")
+	r.FamilyID = field.NewString(table, "family_id")
+	r.UserAgent = field.NewString(table, "user_agent")
+	r.DeviceName = field.NewString(table, "device_name")
+	r.LastUsedAt = field.NewTime(table, "last_used_at")
+	r.RevokedAt = field.NewTime(table, "revoked_at")
+	r.SessionStartedAt = field.NewTime(table, "session_started_at")
+	r.ExpiresAt = field.NewTime(table, "expires_at")
 
 	r.fillFieldMap()
 
@@ -87,13 +117,23 @@ func (r *refreshToken) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (r *refreshToken) fillFieldMap() {
-	r.fieldMap = make(map[string]field.Expr, 6)
+	r.fieldMap = make(map[string]field.Expr, 16)
 	r.fieldMap["id"] = r.ID
 	r.fieldMap["user_id"] = r.UserID
 	r.fieldMap["hashed_token"] = r.HashedToken
 	r.fieldMap["ip_address"] = r.IPAddress
 	r.fieldMap["used"] = r.Used
 	r.fieldMap["created_at"] = r.CreatedAt
+	r.fieldMap["client_id"] = r.ClientID
+	r.fieldMap["scope"] = r.Scope
+	r.fieldMap["amr"] = r.Amr
+	r.fieldMap["family_id"] = r.FamilyID
+	r.fieldMap["user_agent"] = r.UserAgent
+	r.fieldMap["device_name"] = r.DeviceName
+	r.fieldMap["last_used_at"] = r.LastUsedAt
+	r.fieldMap["revoked_at"] = r.RevokedAt
+	r.fieldMap["session_started_at"] = r.SessionStartedAt
+	r.fieldMap["expires_at"] = r.ExpiresAt
 }
 
 func (r refreshToken) clone(db *gorm.DB) refreshToken {
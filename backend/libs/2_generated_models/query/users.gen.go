@@ -32,6 +32,8 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 	_user.Password = field.NewString(tableName, "password")
 	_user.CreatedAt = field.NewTime(tableName, "created_at")
 	_user.UpdatedAt = field.NewTime(tableName, "updated_at")
+	_user.EmailVerifiedAt = field.NewTime(tableName, "email_verified_at")
+	_user.MaxSessions = field.NewInt32(tableName, "max_sessions")
 
 	_user.fillFieldMap()
 
@@ -41,12 +43,14 @@ func newUser(db *gorm.DB, opts ...gen.DOOption) user {
 type user struct {
 	userDo
 
-	ALL       field.Asterisk
-	ID        field.String
-	Email     field.String
-	Password  field.String
-	CreatedAt field.Time
-	UpdatedAt field.Time
+	ALL             field.Asterisk
+	ID              field.String
+	Email           field.String
+	Password        field.String
+	CreatedAt       field.Time
+	UpdatedAt       field.Time
+	EmailVerifiedAt field.Time
+	MaxSessions     field.Int32
 
 	fieldMap map[string]field.Expr
 }
@@ -68,6 +72,8 @@ func (u *user) updateTableName(table string) *user {
 	u.Password = field.NewString(table, "password")
 	u.CreatedAt = field.NewTime(table, "created_at")
 	u.UpdatedAt = field.NewTime(table, "updated_at")
+	u.EmailVerifiedAt = field.NewTime(table, "email_verified_at")
+	u.MaxSessions = field.NewInt32(table, "max_sessions")
 
 	u.fillFieldMap()
 
@@ -84,12 +90,14 @@ func (u *user) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *user) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 5)
+	u.fieldMap = make(map[string]field.Expr, 7)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["email"] = u.Email
 	u.fieldMap["password"] = u.Password
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
+	u.fieldMap["email_verified_at"] = u.EmailVerifiedAt
+	u.fieldMap["max_sessions"] = u.MaxSessions
 }
 
 func (u user) clone(db *gorm.DB) user {
@@ -0,0 +1,53 @@
+package auditor
+
+import (
+	"context"
+	"encoding/json"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+
+	"gorm.io/gorm"
+)
+
+var _ smart_context.IAuditor = (*PostgresAuditor)(nil)
+
+type PostgresAuditor struct {
+	sctx smart_context.ISmartContext
+	db   *gorm.DB
+}
+
+func NewPostgresAuditor(sctx smart_context.ISmartContext, db *gorm.DB) *PostgresAuditor {
+	return &PostgresAuditor{
+		sctx: sctx,
+		db:   db,
+	}
+}
+
+func (a *PostgresAuditor) Record(ctx context.Context, event types.AuditEvent) {
+	details := "{}"
+	if len(event.Details) > 0 {
+		data, err := json.Marshal(event.Details)
+		if err != nil {
+			a.sctx.Errorf("audit details marshal error: %v", err)
+		} else {
+			details = string(data)
+		}
+	}
+
+	row := model.AuditEvent{
+		UserID:    event.UserID,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		EventType: string(event.EventType),
+		Outcome:   string(event.Outcome),
+		RequestID: event.RequestID,
+		Details:   details,
+	}
+
+	a.sctx.Infof("audit: %s %s user=%s ip=%s request_id=%s", row.EventType, row.Outcome, row.UserID, row.IPAddress, row.RequestID)
+
+	if err := a.db.WithContext(ctx).Create(&row).Error; err != nil {
+		a.sctx.Errorf("audit record error: %v", err)
+	}
+}
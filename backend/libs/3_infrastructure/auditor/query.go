@@ -0,0 +1,101 @@
+package auditor
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type Filter struct {
+	UserID    string
+	EventType string
+	Outcome   string
+	IPAddress string
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Cursor    string
+}
+
+// Query возвращает события от новых к старым и курсор следующей страницы (пустой, если страниц больше нет)
+func Query(sctx smart_context.ISmartContext, filter Filter) ([]model.AuditEvent, string, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	q := sctx.GetDB().Model(&model.AuditEvent{})
+	if filter.UserID != "" {
+		q = q.Where("user_id = ?", filter.UserID)
+	}
+	if filter.EventType != "" {
+		q = q.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Outcome != "" {
+		q = q.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.IPAddress != "" {
+		q = q.Where("ip_address = ?", filter.IPAddress)
+	}
+	if !filter.From.IsZero() {
+		q = q.Where("created_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("created_at < ?", filter.To.UTC())
+	}
+	if filter.Cursor != "" {
+		createdAt, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		q = q.Where("(created_at, id) < (?, ?)", createdAt, id)
+	}
+
+	var events []model.AuditEvent
+	if err := q.Order("created_at DESC, id DESC").Limit(limit + 1).Find(&events).Error; err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[len(events)-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return events, nextCursor, nil
+}
+
+func encodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + id))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	createdAtStr, id, ok := strings.Cut(string(data), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+
+	return createdAt, id, nil
+}
@@ -0,0 +1,60 @@
+package auditor
+
+import (
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+const (
+	retentionInterval  = time.Hour
+	retentionBatchSize = 5000
+)
+
+// StartRetention раз в час, пока не отменён контекст sctx, удаляет события старше retention.
+// retention <= 0 — события хранятся бессрочно
+func StartRetention(sctx smart_context.ISmartContext, retention time.Duration) {
+	if retention <= 0 {
+		sctx.Info("Audit retention is disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(retentionInterval)
+		defer ticker.Stop()
+
+		for {
+			deleted, err := Purge(sctx, time.Now().UTC().Add(-retention))
+			if err != nil {
+				sctx.Errorf("audit retention error: %v", err)
+			} else if deleted > 0 {
+				sctx.Infof("Audit retention: deleted %d events older than %s", deleted, retention)
+			}
+
+			select {
+			case <-sctx.GetContext().Done():
+				sctx.Info("Audit retention stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Purge удаляет события старше before пачками, чтобы не держать долгую блокировку таблицы
+func Purge(sctx smart_context.ISmartContext, before time.Time) (int64, error) {
+	var total int64
+	for {
+		result := sctx.GetDB().Exec(
+			`DELETE FROM audit_events WHERE id IN (SELECT id FROM audit_events WHERE created_at < ? LIMIT ?)`,
+			before, retentionBatchSize,
+		)
+		if result.Error != nil {
+			return total, result.Error
+		}
+
+		total += result.RowsAffected
+		if result.RowsAffected < retentionBatchSize {
+			return total, nil
+		}
+	}
+}
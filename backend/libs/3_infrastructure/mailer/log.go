@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"context"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+)
+
+var _ smart_context.IMailer = (*LogMailer)(nil)
+
+type LogMailer struct {
+	sctx smart_context.ISmartContext
+}
+
+func NewLogMailer(sctx smart_context.ISmartContext) *LogMailer {
+	return &LogMailer{sctx: sctx}
+}
+
+func (m *LogMailer) Send(ctx context.Context, message types.MailMessage) error {
+	m.sctx.Infof("MOCK EMAIL to %s: %s\n%s", message.To, message.Subject, message.Text)
+	return nil
+}
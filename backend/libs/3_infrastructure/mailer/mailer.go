@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+)
+
+const (
+	BackendLog  = "log"
+	BackendSMTP = "smtp"
+)
+
+// NewMailer создаёт отправщик писем по переменной MAILER_BACKEND (log по умолчанию).
+// log только пишет письма в лог — для разработки, ссылки из писем попадают в логи
+func NewMailer(sctx smart_context.ISmartContext) (smart_context.IMailer, error) {
+	backend := strings.ToLower(os.Getenv("MAILER_BACKEND"))
+	switch backend {
+	case "", BackendLog:
+		sctx.Warn("Using log mailer: emails are written to the log instead of being sent")
+		return NewLogMailer(sctx), nil
+	case BackendSMTP:
+		cfg := SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     env_vars.GetEnvAsInt(sctx, "SMTP_PORT", 587),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		}
+		if cfg.Host == "" || cfg.From == "" {
+			return nil, fmt.Errorf("smtp mailer requires SMTP_HOST and MAIL_FROM")
+		}
+		sctx.Infof("Using smtp mailer %s:%d", cfg.Host, cfg.Port)
+		return NewSMTPMailer(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown MAILER_BACKEND %q", backend)
+	}
+}
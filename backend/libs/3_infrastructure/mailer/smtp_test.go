@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"test-task3/libs/4_common/types"
+	"testing"
+	"time"
+)
+
+func TestBuildMessage(t *testing.T) {
+	text := strings.Repeat("Сброс пароля: https://example.com/reset?token=abc\n", 5)
+	data, err := buildMessage("noreply@example.com", types.MailMessage{
+		To:      "user@example.com",
+		Subject: "Сброс пароля",
+		Text:    text,
+	}, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	headers, body, ok := strings.Cut(string(data), "\r\n\r\n")
+	if !ok {
+		t.Fatalf("no header/body separator in %q", data)
+	}
+	for _, want := range []string{
+		"From: noreply@example.com",
+		"To: user@example.com",
+		"Subject: =?utf-8?q?",
+		"Date: Thu, 02 Jan 2025 03:04:05 +0000",
+		"Content-Transfer-Encoding: base64",
+	} {
+		if !strings.Contains(headers, want) {
+			t.Errorf("headers %q do not contain %q", headers, want)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n")
+	for _, line := range lines {
+		if len(line) > 76 {
+			t.Errorf("body line is %d characters long", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if string(decoded) != text {
+		t.Errorf("body = %q, want %q", decoded, text)
+	}
+}
+
+func TestBuildMessageRejectsHeaderInjection(t *testing.T) {
+	cases := []types.MailMessage{
+		{To: "user@example.com\r\nBcc: attacker@example.com", Subject: "reset"},
+		{To: "user@example.com", Subject: "reset\nBcc: attacker@example.com"},
+	}
+	for _, message := range cases {
+		if _, err := buildMessage("noreply@example.com", message, time.Now()); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("buildMessage(%q, %q) error = %v, want ErrInvalidHeader", message.To, message.Subject, err)
+		}
+	}
+}
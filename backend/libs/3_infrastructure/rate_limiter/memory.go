@@ -0,0 +1,73 @@
+package rate_limiter
+
+import (
+	"context"
+	"sync"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+const memorySweepInterval = time.Minute
+
+var _ smart_context.IRateLimiter = (*MemoryRateLimiter)(nil)
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     types.RateLimit
+}
+
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets:   make(map[string]*memoryBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *MemoryRateLimiter) Allow(ctx context.Context, key string, limit types.RateLimit) (bool, time.Duration, error) {
+	if !limit.Enabled() {
+		return true, 0, nil
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.Burst), updatedAt: now}
+		l.buckets[key] = bucket
+	}
+	bucket.limit = limit
+
+	tokens, allowed, retryAfter := takeToken(bucket.tokens, bucket.updatedAt, now, limit)
+	bucket.tokens = tokens
+	bucket.updatedAt = now
+
+	return allowed, retryAfter, nil
+}
+
+// sweep удаляет bucket'ы, которые уже успели пополниться полностью — они ничем не отличаются от новых
+func (l *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < memorySweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, bucket := range l.buckets {
+		refillTime := time.Duration(float64(bucket.limit.Burst) / bucket.limit.Rate * float64(time.Second))
+		if now.Sub(bucket.updatedAt) >= refillTime {
+			delete(l.buckets, key)
+		}
+	}
+}
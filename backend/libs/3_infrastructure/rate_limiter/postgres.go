@@ -0,0 +1,94 @@
+package rate_limiter
+
+import (
+	"context"
+	"sync"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	postgresSweepInterval = 10 * time.Minute
+	postgresBucketMaxAge  = 24 * time.Hour
+)
+
+var _ smart_context.IRateLimiter = (*PostgresRateLimiter)(nil)
+
+type PostgresRateLimiter struct {
+	sctx smart_context.ISmartContext
+	db   *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresRateLimiter(sctx smart_context.ISmartContext, db *gorm.DB) *PostgresRateLimiter {
+	return &PostgresRateLimiter{
+		sctx:      sctx,
+		db:        db,
+		lastSweep: time.Now(),
+	}
+}
+
+func (l *PostgresRateLimiter) Allow(ctx context.Context, key string, limit types.RateLimit) (bool, time.Duration, error) {
+	if !limit.Enabled() {
+		return true, 0, nil
+	}
+
+	l.sweep(ctx)
+
+	var allowed bool
+	var retryAfter time.Duration
+
+	err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+
+		bucket := model.RateLimitBucket{
+			Key:       key,
+			Tokens:    float64(limit.Burst),
+			UpdatedAt: now,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bucket).Error; err != nil {
+			return err
+		}
+
+		// строка блокируется до конца транзакции, поэтому параллельные инстансы не потеряют списание
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&bucket).Error; err != nil {
+			return err
+		}
+
+		var tokens float64
+		tokens, allowed, retryAfter = takeToken(bucket.Tokens, bucket.UpdatedAt, now, limit)
+
+		return tx.Model(&model.RateLimitBucket{}).Where("key = ?", key).Updates(map[string]interface{}{
+			"tokens":     tokens,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return false, 0, err
+	}
+
+	return allowed, retryAfter, nil
+}
+
+// sweep периодически удаляет давно не используемые bucket'ы, чтобы таблица не росла бесконечно
+func (l *PostgresRateLimiter) sweep(ctx context.Context) {
+	l.mu.Lock()
+	if time.Since(l.lastSweep) < postgresSweepInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.lastSweep = time.Now()
+	l.mu.Unlock()
+
+	threshold := time.Now().UTC().Add(-postgresBucketMaxAge)
+	if err := l.db.WithContext(ctx).Where("updated_at < ?", threshold).Delete(&model.RateLimitBucket{}).Error; err != nil {
+		l.sctx.Errorf("rate limiter sweep error: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package rate_limiter
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+// NewRateLimiter создаёт rate limiter по переменной RATE_LIMIT_BACKEND (memory по умолчанию).
+// Для нескольких инстансов нужен postgres — тогда bucket'ы общие.
+func NewRateLimiter(sctx smart_context.ISmartContext) (smart_context.IRateLimiter, error) {
+	backend := strings.ToLower(os.Getenv("RATE_LIMIT_BACKEND"))
+	switch backend {
+	case "", BackendMemory:
+		sctx.Infof("Using in-memory rate limiter")
+		return NewMemoryRateLimiter(), nil
+	case BackendPostgres:
+		db := sctx.GetDB()
+		if db == nil {
+			return nil, fmt.Errorf("postgres rate limiter requires a database")
+		}
+		sctx.Infof("Using postgres rate limiter")
+		return NewPostgresRateLimiter(sctx, db), nil
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_BACKEND %q", backend)
+	}
+}
+
+// takeToken пополняет bucket за прошедшее время и пытается забрать из него один токен
+func takeToken(tokens float64, updatedAt, now time.Time, limit types.RateLimit) (float64, bool, time.Duration) {
+	elapsed := now.Sub(updatedAt).Seconds()
+	if elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed*limit.Rate)
+	}
+
+	if tokens >= 1 {
+		return tokens - 1, true, 0
+	}
+
+	retryAfter := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	return tokens, false, retryAfter
+}
@@ -0,0 +1,72 @@
+package rate_limiter
+
+import (
+	"test-task3/libs/4_common/types"
+	"testing"
+	"time"
+)
+
+func TestTakeToken(t *testing.T) {
+	// 2 запроса в секунду, всплеск до 4
+	limit := types.RateLimit{Rate: 2, Burst: 4}
+	start := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		tokens         float64
+		elapsed        time.Duration
+		wantTokens     float64
+		wantAllowed    bool
+		wantRetryAfter time.Duration
+	}{
+		{"full bucket", 4, 0, 3, true, 0},
+		{"last token", 1, 0, 0, true, 0},
+		{"empty bucket", 0, 0, 0, false, 500 * time.Millisecond},
+		{"half token", 0.5, 0, 0.5, false, 250 * time.Millisecond},
+		{"refilled", 0, 500 * time.Millisecond, 0, true, 0},
+		{"partly refilled", 0, 250 * time.Millisecond, 0.5, false, 250 * time.Millisecond},
+		// пополнение не превышает burst
+		{"capped at burst", 0, time.Hour, 3, true, 0},
+		// часы другого инстанса могут отставать: отрицательный интервал не отнимает токены
+		{"clock went back", 2, -time.Second, 1, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, allowed, retryAfter := takeToken(tt.tokens, start, start.Add(tt.elapsed), limit)
+			if tokens != tt.wantTokens || allowed != tt.wantAllowed || retryAfter != tt.wantRetryAfter {
+				t.Errorf("takeToken() = %v, %v, %s, want %v, %v, %s",
+					tokens, allowed, retryAfter, tt.wantTokens, tt.wantAllowed, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestTakeTokenSequence(t *testing.T) {
+	limit := types.RateLimit{Rate: 1, Burst: 3}
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tokens := float64(limit.Burst)
+	updatedAt := now
+	allowed := 0
+	// 10 запросов подряд: проходит только всплеск
+	for range 10 {
+		var ok bool
+		tokens, ok, _ = takeToken(tokens, updatedAt, now, limit)
+		if ok {
+			allowed++
+		}
+	}
+	if allowed != limit.Burst {
+		t.Errorf("allowed %d of 10 requests at once, want %d", allowed, limit.Burst)
+	}
+
+	// через секунду накапливается ровно один токен
+	tokens, ok, _ := takeToken(tokens, updatedAt, now.Add(time.Second), limit)
+	if !ok {
+		t.Error("request after refill was rejected")
+	}
+	if _, ok, _ = takeToken(tokens, now.Add(time.Second), now.Add(time.Second), limit); ok {
+		t.Error("second request after a one-token refill was allowed")
+	}
+}
@@ -0,0 +1,53 @@
+package token_service
+
+import (
+	"os"
+	"test-task3/libs/4_common/env_vars"
+	"test-task3/libs/4_common/smart_context"
+	"time"
+)
+
+type Config struct {
+	Issuer   string
+	Audience string
+	// Leeway — допустимое расхождение часов при проверке exp, nbf и iat
+	Leeway    time.Duration
+	AccessTTL time.Duration
+	// MaxAccessTTL — потолок срока любого access-токена, в том числе с TTL клиента OAuth.
+	// Столько же хранится запись о массовом отзыве токенов пользователя
+	MaxAccessTTL time.Duration
+	// AcceptLegacy разрешает токены старого формата без iss, aud и sub, пока они не истекут
+	AcceptLegacy bool
+	// RevocationCacheTTL — сколько помнить, что токен не отозван, прежде чем снова спросить базу
+	RevocationCacheTTL time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Issuer:       "test-task3",
+		Audience:     "test-task3",
+		Leeway:       30 * time.Second,
+		AccessTTL:    15 * time.Minute,
+		MaxAccessTTL: 24 * time.Hour,
+		AcceptLegacy: true,
+
+		RevocationCacheTTL: 10 * time.Second,
+	}
+}
+
+func LoadConfig(sctx smart_context.ISmartContext) Config {
+	cfg := DefaultConfig()
+	if issuer := os.Getenv("JWT_ISSUER"); issuer != "" {
+		cfg.Issuer = issuer
+	}
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		cfg.Audience = audience
+	}
+	cfg.Leeway = time.Duration(env_vars.GetEnvAsInt(sctx, "JWT_LEEWAY_SECONDS", int(cfg.Leeway.Seconds()))) * time.Second
+	cfg.AccessTTL = time.Duration(env_vars.GetEnvAsInt(sctx, "JWT_ACCESS_TTL_SECONDS", int(cfg.AccessTTL.Seconds()))) * time.Second
+	cfg.MaxAccessTTL = time.Duration(env_vars.GetEnvAsInt(sctx, "JWT_MAX_ACCESS_TTL_SECONDS", int(cfg.MaxAccessTTL.Seconds()))) * time.Second
+	cfg.MaxAccessTTL = max(cfg.MaxAccessTTL, cfg.AccessTTL)
+	cfg.AcceptLegacy = os.Getenv("JWT_ACCEPT_LEGACY_TOKENS") != "false"
+	cfg.RevocationCacheTTL = time.Duration(env_vars.GetEnvAsInt(sctx, "TOKEN_REVOCATION_CACHE_SECONDS", int(cfg.RevocationCacheTTL.Seconds()))) * time.Second
+	return cfg
+}
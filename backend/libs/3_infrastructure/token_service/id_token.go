@@ -0,0 +1,142 @@
+package token_service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var errIDTokensDisabled = errors.New("id token signing key is not configured")
+
+// jwtIDTokenClaims — представление IDTokenClaims на проводе
+type jwtIDTokenClaims struct {
+	jwt.RegisteredClaims
+	AuthorizedParty string   `json:"azp,omitempty"`
+	AuthTime        int64    `json:"auth_time,omitempty"`
+	Nonce           string   `json:"nonce,omitempty"`
+	AMR             []string `json:"amr,omitempty"`
+	Email           string   `json:"email,omitempty"`
+	EmailVerified   *bool    `json:"email_verified,omitempty"`
+}
+
+// LoadIDTokenKey читает RSA-ключ подписи ID-токенов из PEM-файла OIDC_SIGNING_KEY_FILE (PKCS#1 или PKCS#8).
+// Без файла генерируется временный ключ: ID-токены перестанут проверяться после перезапуска
+// и не совпадут между инстансами
+func LoadIDTokenKey(sctx smart_context.ISmartContext) (*rsa.PrivateKey, error) {
+	path := os.Getenv("OIDC_SIGNING_KEY_FILE")
+	if path == "" {
+		sctx.Warn("OIDC_SIGNING_KEY_FILE is not set, using an ephemeral key for ID tokens")
+		return rsa.GenerateKey(rand.Reader, 2048)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an RSA key", path)
+	}
+	return key, nil
+}
+
+// WithIDTokenKey включает выпуск ID-токенов (RS256) этим ключом
+func (s *JwtTokenService) WithIDTokenKey(key *rsa.PrivateKey) *JwtTokenService {
+	s.idTokenKey = key
+	s.idTokenKeyID = keyThumbprint(&key.PublicKey)
+	return s
+}
+
+func (s *JwtTokenService) IssueIDToken(claims types.IDTokenClaims) (string, error) {
+	if s.idTokenKey == nil {
+		return "", errIDTokensDisabled
+	}
+
+	now := s.now().Truncate(time.Second)
+	if claims.Issuer == "" {
+		claims.Issuer = s.cfg.Issuer
+	}
+	if claims.IssuedAt.IsZero() {
+		claims.IssuedAt = now
+	}
+	if claims.ExpiresAt.IsZero() {
+		claims.ExpiresAt = now.Add(s.cfg.AccessTTL)
+	}
+
+	raw := jwtIDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    claims.Issuer,
+			Subject:   claims.Subject,
+			Audience:  claims.Audience,
+			ExpiresAt: numericDate(claims.ExpiresAt),
+			IssuedAt:  numericDate(claims.IssuedAt),
+		},
+		AuthorizedParty: claims.AuthorizedParty,
+		Nonce:           claims.Nonce,
+		AMR:             claims.AMR,
+		Email:           claims.Email,
+	}
+	if !claims.AuthTime.IsZero() {
+		raw.AuthTime = claims.AuthTime.Unix()
+	}
+	// email_verified имеет смысл только вместе с email
+	if claims.Email != "" {
+		raw.EmailVerified = &claims.EmailVerified
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, raw)
+	token.Header["kid"] = s.idTokenKeyID
+	return token.SignedString(s.idTokenKey)
+}
+
+func (s *JwtTokenService) JWKS() types.JSONWebKeySet {
+	set := types.JSONWebKeySet{Keys: []types.JSONWebKey{}}
+	if s.idTokenKey != nil {
+		set.Keys = append(set.Keys, publicJWK(&s.idTokenKey.PublicKey, s.idTokenKeyID))
+	}
+	return set
+}
+
+func publicJWK(key *rsa.PublicKey, kid string) types.JSONWebKey {
+	return types.JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+// keyThumbprint — JWK thumbprint (RFC 7638): kid не меняется, пока не сменится ключ
+func keyThumbprint(key *rsa.PublicKey) string {
+	jwk := publicJWK(key, "")
+	canonical := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, jwk.E, jwk.N)
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
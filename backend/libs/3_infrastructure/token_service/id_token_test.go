@@ -0,0 +1,93 @@
+package token_service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"test-task3/libs/4_common/types"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ID-токен должен проверяться только по опубликованному JWKS, как это делают сторонние OIDC-клиенты
+func TestIDTokenVerifiesWithJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newTestService(DefaultConfig()).WithIDTokenKey(key)
+
+	authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	token, err := service.IssueIDToken(types.IDTokenClaims{
+		Subject:  "user-5",
+		Audience: []string{"spa"},
+		AuthTime: authTime,
+		Nonce:    "n-0S6_WzA2Mj",
+		AMR:      []string{"pwd"},
+		Email:    "user5@example.com",
+	})
+	if err != nil {
+		t.Fatalf("IssueIDToken() error = %v", err)
+	}
+
+	jwks := service.JWKS()
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("JWKS() has %d keys, want 1", len(jwks.Keys))
+	}
+	jwk := jwks.Keys[0]
+
+	var claims struct {
+		jwt.RegisteredClaims
+		AuthTime int64    `json:"auth_time"`
+		Nonce    string   `json:"nonce"`
+		AMR      []string `json:"amr"`
+		Email    string   `json:"email"`
+		// указатель — чтобы отличить false от отсутствия claim
+		EmailVerified *bool `json:"email_verified"`
+	}
+	parsed, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Header["kid"] != jwk.Kid {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return publicKeyFromJWK(jwk)
+	}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer("test-task3"), jwt.WithAudience("spa"))
+	if err != nil || !parsed.Valid {
+		t.Fatalf("ParseWithClaims() error = %v", err)
+	}
+
+	if claims.Subject != "user-5" || claims.Nonce != "n-0S6_WzA2Mj" || claims.AuthTime != authTime.Unix() {
+		t.Errorf("ID token claims = %+v", claims)
+	}
+	if len(claims.AMR) != 1 || claims.AMR[0] != "pwd" {
+		t.Errorf("ID token amr = %v, want [pwd]", claims.AMR)
+	}
+	if claims.Email != "user5@example.com" || claims.EmailVerified == nil || *claims.EmailVerified {
+		t.Errorf("ID token email = %q, email_verified = %v, want unverified user5@example.com", claims.Email, claims.EmailVerified)
+	}
+}
+
+func TestIDTokenWithoutKey(t *testing.T) {
+	service := newTestService(DefaultConfig())
+
+	if _, err := service.IssueIDToken(types.IDTokenClaims{Subject: "user-5"}); err == nil {
+		t.Error("IssueIDToken() without key succeeded")
+	}
+	if keys := service.JWKS().Keys; len(keys) != 0 {
+		t.Errorf("JWKS() without key = %v", keys)
+	}
+}
+
+func publicKeyFromJWK(jwk types.JSONWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+}
@@ -0,0 +1,236 @@
+package token_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var _ smart_context.ITokenService = (*JwtTokenService)(nil)
+
+// jwtAccessClaims — представление AccessClaims на проводе
+type jwtAccessClaims struct {
+	jwt.RegisteredClaims
+	UserID   string   `json:"user_id,omitempty"`
+	IP       string   `json:"ip,omitempty"`
+	ClientID string   `json:"client_id,omitempty"`
+	Scope    string   `json:"scope,omitempty"`
+	Roles    []string `json:"roles,omitempty"`
+	AMR      []string `json:"amr,omitempty"`
+	SID      string   `json:"sid,omitempty"`
+}
+
+var errRevocationDisabled = errors.New("token revocation is not configured")
+
+type JwtTokenService struct {
+	secret      []byte
+	cfg         Config
+	parser      *jwt.Parser
+	revocations *RevocationStore
+	now         func() time.Time
+
+	idTokenKey   *rsa.PrivateKey
+	idTokenKeyID string
+}
+
+// NewJwtTokenService создаёт сервис HS512-токенов. Без revocations отзыв токенов недоступен
+func NewJwtTokenService(secret string, cfg Config, revocations *RevocationStore) *JwtTokenService {
+	return &JwtTokenService{
+		secret:      []byte(secret),
+		cfg:         cfg,
+		revocations: revocations,
+		// время, iss и aud проверяем сами: legacy-токены их не содержат
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS512.Alg()}),
+			jwt.WithoutClaimsValidation(),
+		),
+		now: time.Now,
+	}
+}
+
+func (s *JwtTokenService) IssueAccessToken(claims types.AccessClaims) (string, *types.AccessClaims, error) {
+	now := s.now().Truncate(time.Second)
+
+	if claims.ID == "" {
+		jti, err := newTokenID()
+		if err != nil {
+			return "", nil, err
+		}
+		claims.ID = jti
+	}
+	if claims.Issuer == "" {
+		claims.Issuer = s.cfg.Issuer
+	}
+	if len(claims.Audience) == 0 {
+		claims.Audience = []string{s.cfg.Audience}
+	}
+	if claims.IssuedAt.IsZero() {
+		claims.IssuedAt = now
+	}
+	if claims.NotBefore.IsZero() {
+		claims.NotBefore = now
+	}
+	if claims.ExpiresAt.IsZero() {
+		claims.ExpiresAt = now.Add(s.cfg.AccessTTL)
+	}
+	// иначе токен пережил бы запись о массовом отзыве
+	if s.cfg.MaxAccessTTL > 0 && claims.ExpiresAt.After(now.Add(s.cfg.MaxAccessTTL)) {
+		claims.ExpiresAt = now.Add(s.cfg.MaxAccessTTL)
+	}
+	if claims.UserID == "" && !claims.IsClientToken() {
+		claims.UserID = claims.Subject
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS512, toJwtClaims(claims))
+	signed, err := token.SignedString(s.secret)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, &claims, nil
+}
+
+func (s *JwtTokenService) ParseAccessToken(tokenString string) (*types.AccessClaims, error) {
+	var raw jwtAccessClaims
+	_, err := s.parser.ParseWithClaims(tokenString, &raw, func(t *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	})
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenMalformed) {
+			return nil, fmt.Errorf("%w: %v", types.ErrTokenMalformed, err)
+		}
+		return nil, fmt.Errorf("%w: %v", types.ErrTokenSignature, err)
+	}
+
+	claims := fromJwtClaims(raw)
+	if err := s.validate(claims); err != nil {
+		if errors.Is(err, types.ErrTokenExpired) {
+			return claims, err
+		}
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (s *JwtTokenService) IsRevoked(ctx context.Context, claims *types.AccessClaims) (bool, error) {
+	if s.revocations == nil {
+		return false, nil
+	}
+	return s.revocations.IsRevoked(ctx, claims)
+}
+
+func (s *JwtTokenService) Revoke(ctx context.Context, claims *types.AccessClaims, reason string) error {
+	if s.revocations == nil {
+		return errRevocationDisabled
+	}
+	return s.revocations.Revoke(ctx, claims, reason)
+}
+
+func (s *JwtTokenService) RevokeAllForUser(ctx context.Context, userId string, before time.Time, reason string) error {
+	if s.revocations == nil {
+		return errRevocationDisabled
+	}
+	return s.revocations.RevokeAllForUser(ctx, userId, before, reason)
+}
+
+func (s *JwtTokenService) validate(claims *types.AccessClaims) error {
+	now := s.now()
+
+	if claims.GetUserID() == "" || claims.ExpiresAt.IsZero() {
+		return types.ErrTokenMissingClaims
+	}
+
+	legacy := claims.Issuer == "" && len(claims.Audience) == 0 && claims.Subject == ""
+	if !legacy || !s.cfg.AcceptLegacy {
+		if claims.Issuer != s.cfg.Issuer {
+			return types.ErrTokenIssuer
+		}
+		if !slices.Contains(claims.Audience, s.cfg.Audience) {
+			return types.ErrTokenAudience
+		}
+	}
+
+	if !claims.NotBefore.IsZero() && now.Add(s.cfg.Leeway).Before(claims.NotBefore) {
+		return types.ErrTokenNotYetValid
+	}
+	if !claims.IssuedAt.IsZero() && now.Add(s.cfg.Leeway).Before(claims.IssuedAt) {
+		return types.ErrTokenNotYetValid
+	}
+	if now.Add(-s.cfg.Leeway).After(claims.ExpiresAt) {
+		return types.ErrTokenExpired
+	}
+
+	return nil
+}
+
+func toJwtClaims(claims types.AccessClaims) jwtAccessClaims {
+	return jwtAccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    claims.Issuer,
+			Subject:   claims.Subject,
+			Audience:  claims.Audience,
+			ExpiresAt: numericDate(claims.ExpiresAt),
+			NotBefore: numericDate(claims.NotBefore),
+			IssuedAt:  numericDate(claims.IssuedAt),
+			ID:        claims.ID,
+		},
+		UserID:   claims.UserID,
+		IP:       claims.IP,
+		ClientID: claims.ClientID,
+		Scope:    claims.Scope,
+		Roles:    claims.Roles,
+		AMR:      claims.AMR,
+		SID:      claims.SessionID,
+	}
+}
+
+func fromJwtClaims(raw jwtAccessClaims) *types.AccessClaims {
+	return &types.AccessClaims{
+		Issuer:    raw.Issuer,
+		Subject:   raw.Subject,
+		Audience:  raw.Audience,
+		ExpiresAt: timeOf(raw.ExpiresAt),
+		NotBefore: timeOf(raw.NotBefore),
+		IssuedAt:  timeOf(raw.IssuedAt),
+		ID:        raw.ID,
+		UserID:    raw.UserID,
+		IP:        raw.IP,
+		ClientID:  raw.ClientID,
+		Scope:     raw.Scope,
+		Roles:     raw.Roles,
+		AMR:       raw.AMR,
+		SessionID: raw.SID,
+	}
+}
+
+func numericDate(t time.Time) *jwt.NumericDate {
+	if t.IsZero() {
+		return nil
+	}
+	return jwt.NewNumericDate(t)
+}
+
+func timeOf(date *jwt.NumericDate) time.Time {
+	if date == nil {
+		return time.Time{}
+	}
+	return date.Time
+}
+
+func newTokenID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,215 @@
+package token_service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const revocationSweepInterval = 10 * time.Minute
+
+type tokenCacheEntry struct {
+	revoked    bool
+	cacheUntil time.Time
+}
+
+type userCacheEntry struct {
+	revokedBefore time.Time
+	cacheUntil    time.Time
+}
+
+// RevocationStore хранит отозванные токены в Postgres и кэширует проверки в памяти.
+// Отзыв по jti кэшируется до истечения токена, отсутствие отзыва — на negativeTTL,
+// поэтому отзыв с другого инстанса начинает действовать не позже чем через negativeTTL
+type RevocationStore struct {
+	sctx        smart_context.ISmartContext
+	db          *gorm.DB
+	negativeTTL time.Duration
+	maxTokenTTL time.Duration
+
+	mu        sync.Mutex
+	tokens    map[string]tokenCacheEntry
+	users     map[string]userCacheEntry
+	lastSweep time.Time
+}
+
+func NewRevocationStore(sctx smart_context.ISmartContext, db *gorm.DB, negativeTTL, maxTokenTTL time.Duration) *RevocationStore {
+	return &RevocationStore{
+		sctx:        sctx,
+		db:          db,
+		negativeTTL: negativeTTL,
+		maxTokenTTL: maxTokenTTL,
+		tokens:      make(map[string]tokenCacheEntry),
+		users:       make(map[string]userCacheEntry),
+		lastSweep:   time.Now(),
+	}
+}
+
+func (s *RevocationStore) IsRevoked(ctx context.Context, claims *types.AccessClaims) (bool, error) {
+	now := time.Now()
+	s.sweep(ctx, now)
+
+	revokedBefore, err := s.userRevokedBefore(ctx, claims.GetUserID(), now)
+	if err != nil {
+		return false, err
+	}
+	if issuedBefore(claims.IssuedAt, revokedBefore) {
+		return true, nil
+	}
+
+	if claims.ID == "" {
+		return false, nil
+	}
+	return s.tokenRevoked(ctx, claims, now)
+}
+
+func (s *RevocationStore) Revoke(ctx context.Context, claims *types.AccessClaims, reason string) error {
+	if claims.ID == "" {
+		return errors.New("token has no jti")
+	}
+
+	row := model.RevokedToken{
+		Jti:       claims.ID,
+		UserID:    claims.GetUserID(),
+		Reason:    reason,
+		ExpiresAt: claims.ExpiresAt.UTC(),
+	}
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tokens[claims.ID] = tokenCacheEntry{revoked: true, cacheUntil: claims.ExpiresAt}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *RevocationStore) RevokeAllForUser(ctx context.Context, userId string, before time.Time, reason string) error {
+	before = before.UTC()
+	row := model.UserTokenRevocation{
+		UserID:        userId,
+		RevokedBefore: before,
+		Reason:        reason,
+		// после этого момента все токены, выпущенные до before, истекли сами
+		ExpiresAt: before.Add(s.maxTokenTTL),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"revoked_before": gorm.Expr("GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)"),
+			"expires_at":     gorm.Expr("GREATEST(user_token_revocations.expires_at, EXCLUDED.expires_at)"),
+			"reason":         gorm.Expr("EXCLUDED.reason"),
+			"updated_at":     gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(&row).Error
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.users, userId)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// issuedBefore сообщает, попадает ли токен под массовый отзыв. iat хранится с точностью до секунды,
+// поэтому сравнивается с отметкой отзыва, округлённой вниз: токен, выпущенный в ту же секунду, что и отзыв,
+// остаётся действительным, иначе вход сразу после отзыва отклонялся бы.
+// У legacy-токенов нет iat, поэтому при массовом отзыве они считаются отозванными
+func issuedBefore(issuedAt, revokedBefore time.Time) bool {
+	if revokedBefore.IsZero() {
+		return false
+	}
+	return issuedAt.IsZero() || issuedAt.Before(revokedBefore.Truncate(time.Second))
+}
+
+func (s *RevocationStore) tokenRevoked(ctx context.Context, claims *types.AccessClaims, now time.Time) (bool, error) {
+	s.mu.Lock()
+	entry, ok := s.tokens[claims.ID]
+	s.mu.Unlock()
+	if ok && now.Before(entry.cacheUntil) {
+		return entry.revoked, nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&model.RevokedToken{}).Where("jti = ?", claims.ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	entry = tokenCacheEntry{revoked: count > 0, cacheUntil: now.Add(s.negativeTTL)}
+	if entry.revoked {
+		entry.cacheUntil = claims.ExpiresAt
+	}
+
+	s.mu.Lock()
+	s.tokens[claims.ID] = entry
+	s.mu.Unlock()
+
+	return entry.revoked, nil
+}
+
+func (s *RevocationStore) userRevokedBefore(ctx context.Context, userId string, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	entry, ok := s.users[userId]
+	s.mu.Unlock()
+	if ok && now.Before(entry.cacheUntil) {
+		return entry.revokedBefore, nil
+	}
+
+	var rows []model.UserTokenRevocation
+	err := s.db.WithContext(ctx).Where("user_id = ? AND expires_at > ?", userId, now.UTC()).Limit(1).Find(&rows).Error
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	entry = userCacheEntry{cacheUntil: now.Add(s.negativeTTL)}
+	if len(rows) > 0 {
+		entry.revokedBefore = rows[0].RevokedBefore
+	}
+
+	s.mu.Lock()
+	s.users[userId] = entry
+	s.mu.Unlock()
+
+	return entry.revokedBefore, nil
+}
+
+// sweep чистит протухшие записи кэша и удаляет из базы отзывы, которые пережили сами токены
+func (s *RevocationStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < revocationSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	for jti, entry := range s.tokens {
+		if !now.Before(entry.cacheUntil) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userId, entry := range s.users {
+		if !now.Before(entry.cacheUntil) {
+			delete(s.users, userId)
+		}
+	}
+	s.mu.Unlock()
+
+	db := s.db.WithContext(ctx)
+	if err := db.Where("expires_at < ?", now.UTC()).Delete(&model.RevokedToken{}).Error; err != nil {
+		s.sctx.Errorf("revoked tokens cleanup error: %v", err)
+	}
+	if err := db.Where("expires_at < ?", now.UTC()).Delete(&model.UserTokenRevocation{}).Error; err != nil {
+		s.sctx.Errorf("user token revocations cleanup error: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package token_service
+
+import (
+	"context"
+	"test-task3/libs/2_generated_models/model"
+	"test-task3/libs/4_common/smart_context"
+	"test-task3/libs/4_common/types"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunStore возвращает хранилище поверх gorm в режиме DryRun: запросы строятся, но не выполняются,
+// поэтому проверяется логика кэша без базы. Записанные строки отзыва пользователя попадают в saved
+func newDryRunStore(t *testing.T, maxTokenTTL time.Duration) (*RevocationStore, *[]model.UserTokenRevocation) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+
+	var saved []model.UserTokenRevocation
+	err = db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		if row, ok := tx.Statement.Dest.(*model.UserTokenRevocation); ok {
+			saved = append(saved, *row)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewRevocationStore(smart_context.NewSmartContext(), db, time.Minute, maxTokenTTL), &saved
+}
+
+func TestIssuedBefore(t *testing.T) {
+	revokedAt := time.Date(2030, 1, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name          string
+		issuedAt      time.Time
+		revokedBefore time.Time
+		want          bool
+	}{
+		{"no revocation", revokedAt.Add(-time.Hour), time.Time{}, false},
+		{"issued earlier", revokedAt.Add(-time.Second).Truncate(time.Second), revokedAt, true},
+		// iat усечён до секунды: токен, выпущенный сразу после отзыва, не должен отклоняться
+		{"issued in the same second", revokedAt.Truncate(time.Second), revokedAt, false},
+		{"issued later", revokedAt.Add(time.Second).Truncate(time.Second), revokedAt, false},
+		{"legacy token without iat", time.Time{}, revokedAt, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuedBefore(tt.issuedAt, tt.revokedBefore); got != tt.want {
+				t.Errorf("issuedBefore(%s, %s) = %v, want %v", tt.issuedAt, tt.revokedBefore, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevokeCachesUntilExpiry(t *testing.T) {
+	store, _ := newDryRunStore(t, time.Hour)
+	ctx := context.Background()
+
+	claims := &types.AccessClaims{ID: "jti-1", Subject: "user-1", IssuedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	if err := store.Revoke(ctx, claims, "test"); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	revoked, err := store.IsRevoked(ctx, claims)
+	if err != nil || !revoked {
+		t.Fatalf("IsRevoked() = %v, %v, want true", revoked, err)
+	}
+	if entry := store.tokens["jti-1"]; !entry.cacheUntil.Equal(claims.ExpiresAt) {
+		t.Errorf("cacheUntil = %s, want token exp %s", entry.cacheUntil, claims.ExpiresAt)
+	}
+
+	if err := store.Revoke(ctx, &types.AccessClaims{Subject: "user-1"}, "test"); err == nil {
+		t.Error("Revoke() without jti should fail")
+	}
+}
+
+func TestIsRevokedByUserCache(t *testing.T) {
+	store, _ := newDryRunStore(t, time.Hour)
+	ctx := context.Background()
+
+	revokedBefore := time.Now().Add(-time.Minute)
+	store.users["user-1"] = userCacheEntry{revokedBefore: revokedBefore, cacheUntil: time.Now().Add(time.Minute)}
+
+	tests := []struct {
+		name   string
+		claims *types.AccessClaims
+		want   bool
+	}{
+		{"issued before revocation", &types.AccessClaims{ID: "a", Subject: "user-1", IssuedAt: revokedBefore.Add(-time.Hour)}, true},
+		{"issued after revocation", &types.AccessClaims{ID: "b", Subject: "user-1", IssuedAt: time.Now()}, false},
+		{"other user", &types.AccessClaims{ID: "c", Subject: "user-2", IssuedAt: revokedBefore.Add(-time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.IsRevoked(ctx, tt.claims)
+			if err != nil {
+				t.Fatalf("IsRevoked() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// не найденный в базе отзыв помнится только negativeTTL
+	if entry := store.users["user-2"]; !entry.revokedBefore.IsZero() || entry.cacheUntil.After(time.Now().Add(store.negativeTTL)) {
+		t.Errorf("negative cache entry = %+v", entry)
+	}
+}
+
+func TestRevokeAllForUser(t *testing.T) {
+	store, saved := newDryRunStore(t, 24*time.Hour)
+	store.users["user-1"] = userCacheEntry{cacheUntil: time.Now().Add(time.Minute)}
+
+	before := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.RevokeAllForUser(context.Background(), "user-1", before, "password reset"); err != nil {
+		t.Fatalf("RevokeAllForUser() error = %v", err)
+	}
+
+	// закэшированное «не отозван» должно сбрасываться сразу
+	if _, ok := store.users["user-1"]; ok {
+		t.Error("user cache entry was not dropped")
+	}
+	if len(*saved) != 1 {
+		t.Fatalf("saved %d revocations, want 1", len(*saved))
+	}
+	// запись живёт, пока не истекут самые долгие токены, выпущенные до отзыва
+	if got, want := (*saved)[0].ExpiresAt, before.Add(24*time.Hour); !got.Equal(want) {
+		t.Errorf("ExpiresAt = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+package metrics
+
+import "expvar"
+
+// Метрики публикуются через expvar и отдаются админским роутом /admin/metrics
+var (
+	// IPBindingDecisions считает решения политики привязки токенов к IP в разрезе "<scope>.<policy>.<decision>"
+	IPBindingDecisions = expvar.NewMap("ip_binding_decisions")
+
+	// TokenJanitorRuns считает запуски чистки refresh-токенов: completed, skipped (идёт на другом инстансе), failed
+	TokenJanitorRuns = expvar.NewMap("token_janitor_runs")
+	// TokenJanitorRows считает вычищенные строки: deleted или archived для refresh-токенов, expired для challenge и ссылок
+	TokenJanitorRows = expvar.NewMap("token_janitor_rows")
+	// TokenJanitorLastSuccess — unix-время последней завершённой чистки
+	TokenJanitorLastSuccess = expvar.NewInt("token_janitor_last_success_unix")
+)
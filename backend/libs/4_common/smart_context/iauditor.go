@@ -0,0 +1,11 @@
+package smart_context
+
+import (
+	"context"
+	"test-task3/libs/4_common/types"
+)
+
+type IAuditor interface {
+	// Record сохраняет событие безопасности. Ошибки записи логируются, но не прерывают запрос
+	Record(ctx context.Context, event types.AuditEvent)
+}
@@ -0,0 +1,11 @@
+package smart_context
+
+import (
+	"context"
+	"test-task3/libs/4_common/types"
+)
+
+type IMailer interface {
+	// Send отправляет письмо. Ошибка означает, что письмо не ушло
+	Send(ctx context.Context, message types.MailMessage) error
+}
@@ -0,0 +1,12 @@
+package smart_context
+
+import (
+	"context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+type IRateLimiter interface {
+	// Allow забирает один токен из bucket'а key. Если токенов нет — возвращает false и время до следующего токена
+	Allow(ctx context.Context, key string, limit types.RateLimit) (bool, time.Duration, error)
+}
@@ -35,6 +35,22 @@ type ISmartContext interface {
 	WithDB(db *gorm.DB) ISmartContext
 	GetDB() *gorm.DB
 
+	WithRateLimiter(limiter IRateLimiter) ISmartContext
+	GetRateLimiter() IRateLimiter
+
+	WithAuditor(auditor IAuditor) ISmartContext
+	GetAuditor() IAuditor
+
+	WithMailer(mailer IMailer) ISmartContext
+	GetMailer() IMailer
+
+	WithTokenService(tokenService ITokenService) ISmartContext
+	GetTokenService() ITokenService
+
+	// claims access-токена текущего запроса, заполняются middleware аутентификации
+	WithAccessClaims(claims *types.AccessClaims) ISmartContext
+	GetAccessClaims() *types.AccessClaims
+
 	// Метод для получения стандартного context.Context
 	WithContext(ctx context.Context) ISmartContext
 	GetContext() context.Context
@@ -0,0 +1,25 @@
+package smart_context
+
+import (
+	"context"
+	"test-task3/libs/4_common/types"
+	"time"
+)
+
+// ITokenService скрывает JWT-библиотеку и алгоритмы подписи от хэндлеров
+type ITokenService interface {
+	// IssueAccessToken дополняет claims (iss, aud, iat, nbf, exp, jti) и подписывает токен
+	IssueAccessToken(claims types.AccessClaims) (string, *types.AccessClaims, error)
+	// ParseAccessToken проверяет подпись и claims. Для истёкшего токена возвращает claims вместе с types.ErrTokenExpired
+	ParseAccessToken(tokenString string) (*types.AccessClaims, error)
+	// IsRevoked сообщает, отозван ли токен до истечения срока
+	IsRevoked(ctx context.Context, claims *types.AccessClaims) (bool, error)
+	// Revoke отзывает один токен по jti до конца его срока жизни
+	Revoke(ctx context.Context, claims *types.AccessClaims, reason string) error
+	// RevokeAllForUser отзывает все токены пользователя, выпущенные раньше before (с точностью до секунды)
+	RevokeAllForUser(ctx context.Context, userId string, before time.Time, reason string) error
+	// IssueIDToken подписывает OpenID Connect ID-токен асимметричным ключом, открытая часть — в JWKS
+	IssueIDToken(claims types.IDTokenClaims) (string, error)
+	// JWKS возвращает открытые ключи для проверки ID-токенов
+	JWKS() types.JSONWebKeySet
+}
@@ -0,0 +1,16 @@
+package smart_context
+
+import "context"
+
+type requestContextKey struct{}
+
+// ToContext кладёт ISmartContext запроса в context.Context, чтобы middleware могли передать его хэндлерам
+func ToContext(ctx context.Context, sctx ISmartContext) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, sctx)
+}
+
+// FromContext возвращает ISmartContext запроса, если его положил middleware
+func FromContext(ctx context.Context) (ISmartContext, bool) {
+	sctx, ok := ctx.Value(requestContextKey{}).(ISmartContext)
+	return sctx, ok
+}
@@ -220,6 +220,76 @@ func (sc *SmartContext) GetDB() *gorm.DB {
 	return tx
 }
 
+const RATE_LIMITER_KEY = "rate_limiter"
+
+func (sc *SmartContext) WithRateLimiter(limiter IRateLimiter) ISmartContext {
+	return sc.WithField(RATE_LIMITER_KEY, limiter)
+}
+
+func (sc *SmartContext) GetRateLimiter() IRateLimiter {
+	result, ok := types.GetFieldTypedValue[IRateLimiter](sc.dataFields, RATE_LIMITER_KEY)
+	if !ok {
+		return nil
+	}
+	return result
+}
+
+const AUDITOR_KEY = "auditor"
+
+func (sc *SmartContext) WithAuditor(auditor IAuditor) ISmartContext {
+	return sc.WithField(AUDITOR_KEY, auditor)
+}
+
+func (sc *SmartContext) GetAuditor() IAuditor {
+	result, ok := types.GetFieldTypedValue[IAuditor](sc.dataFields, AUDITOR_KEY)
+	if !ok {
+		return nil
+	}
+	return result
+}
+
+const MAILER_KEY = "mailer"
+
+func (sc *SmartContext) WithMailer(mailer IMailer) ISmartContext {
+	return sc.WithField(MAILER_KEY, mailer)
+}
+
+func (sc *SmartContext) GetMailer() IMailer {
+	result, ok := types.GetFieldTypedValue[IMailer](sc.dataFields, MAILER_KEY)
+	if !ok {
+		return nil
+	}
+	return result
+}
+
+const TOKEN_SERVICE_KEY = "token_service"
+
+func (sc *SmartContext) WithTokenService(tokenService ITokenService) ISmartContext {
+	return sc.WithField(TOKEN_SERVICE_KEY, tokenService)
+}
+
+func (sc *SmartContext) GetTokenService() ITokenService {
+	result, ok := types.GetFieldTypedValue[ITokenService](sc.dataFields, TOKEN_SERVICE_KEY)
+	if !ok {
+		return nil
+	}
+	return result
+}
+
+const ACCESS_CLAIMS_KEY = "access_claims"
+
+func (sc *SmartContext) WithAccessClaims(claims *types.AccessClaims) ISmartContext {
+	return sc.WithField(ACCESS_CLAIMS_KEY, claims)
+}
+
+func (sc *SmartContext) GetAccessClaims() *types.AccessClaims {
+	result, ok := types.GetFieldTypedValue[*types.AccessClaims](sc.dataFields, ACCESS_CLAIMS_KEY)
+	if !ok {
+		return nil
+	}
+	return result
+}
+
 func getLogLevel() zapcore.Level {
 	logLevel := os.Getenv("LOG_LEVEL")
 	switch strings.ToLower(logLevel) {
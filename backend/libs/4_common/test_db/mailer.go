@@ -0,0 +1,37 @@
+package test_db
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"test-task3/libs/4_common/types"
+	"testing"
+)
+
+// Mailer запоминает письма вместо отправки
+type Mailer struct {
+	Sent []types.MailMessage
+}
+
+func (m *Mailer) Send(_ context.Context, message types.MailMessage) error {
+	m.Sent = append(m.Sent, message)
+	return nil
+}
+
+// LastLink достаёт ссылку из последнего письма на адрес to, в котором она есть
+func (m *Mailer) LastLink(t testing.TB, to string) *url.URL {
+	t.Helper()
+
+	for i := len(m.Sent) - 1; i >= 0; i-- {
+		if m.Sent[i].To != to {
+			continue
+		}
+		for _, field := range strings.Fields(m.Sent[i].Text) {
+			if link, err := url.Parse(field); err == nil && (link.Scheme == "https" || link.Scheme == "http") {
+				return link
+			}
+		}
+	}
+	t.Fatalf("no link sent to %s", to)
+	return nil
+}
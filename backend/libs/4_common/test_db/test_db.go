@@ -0,0 +1,138 @@
+// Package test_db открывает SQLite в памяти для тестов доменной логики без Postgres.
+// Таблицы создаются из файлов migration/, поэтому схема в тестах та же, что в базе
+package test_db
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/go-sqlite"
+	gorm_sqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// timeFormat — формат, в котором драйвер пишет time.Time, чтобы now() сравнивался с параметрами запросов
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+var (
+	commentRe        = regexp.MustCompile(`--[^\n]*`)
+	statementTableRe = regexp.MustCompile(`(?is)^(?:CREATE TABLE|CREATE (?:UNIQUE )?INDEX \w+ ON|ALTER TABLE|INSERT INTO|UPDATE)\s+(\w+)`)
+	alterTableRe     = regexp.MustCompile(`(?is)^ALTER TABLE\s+(\w+)\s+(.*)$`)
+	// в SQLite выражение в DEFAULT должно быть в скобках
+	functionDefaultRe = regexp.MustCompile(`(?i)DEFAULT\s+(\w+\(\))`)
+)
+
+func init() {
+	// функции Postgres, которые используют миграции
+	sqlite.MustRegisterScalarFunction("gen_random_uuid", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+	})
+	sqlite.MustRegisterScalarFunction("now", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return time.Now().Format(timeFormat), nil
+	})
+}
+
+// Open создаёт пустую базу с таблицами tables из миграций и закрывает её по окончании теста
+func Open(t testing.TB, tables ...string) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(gorm_sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// у каждого соединения своя база в памяти
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	statements, err := schema(tables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("apply migration: %v\n%s", err, statement)
+		}
+	}
+	return db
+}
+
+// schema выбирает из миграций по порядку запросы к tables и переводит их на диалект SQLite
+func schema(tables []string) ([]string, error) {
+	_, file, _, _ := runtime.Caller(0)
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "..", "..", "..", "migration", "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no migrations found")
+	}
+	sort.Strings(files)
+
+	var statements []string
+	found := map[string]bool{}
+	for _, name := range files {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, statement := range strings.Split(commentRe.ReplaceAllString(string(content), ""), ";") {
+			statement = strings.TrimSpace(statement)
+			match := statementTableRe.FindStringSubmatch(statement)
+			if match == nil || !slices.Contains(tables, match[1]) {
+				continue
+			}
+			found[match[1]] = true
+
+			statement = functionDefaultRe.ReplaceAllString(statement, "DEFAULT ($1)")
+			statements = append(statements, splitAlterTable(statement)...)
+		}
+	}
+
+	for _, table := range tables {
+		if !found[table] {
+			return nil, fmt.Errorf("table %s not found in migrations", table)
+		}
+	}
+	return statements, nil
+}
+
+// splitAlterTable разбивает ALTER TABLE с несколькими действиями на отдельные запросы: SQLite принимает
+// по одному. ALTER COLUMN пропускается — он только ужесточает ограничения, которых SQLite не меняет
+func splitAlterTable(statement string) []string {
+	match := alterTableRe.FindStringSubmatch(statement)
+	if match == nil {
+		return []string{statement}
+	}
+
+	var statements []string
+	for _, action := range strings.Split(match[2], ",") {
+		action = strings.TrimSpace(action)
+		if strings.HasPrefix(strings.ToUpper(action), "ALTER COLUMN") {
+			continue
+		}
+		statements = append(statements, "ALTER TABLE "+match[1]+" "+action)
+	}
+	return statements
+}
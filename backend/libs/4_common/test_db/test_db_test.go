@@ -0,0 +1,48 @@
+package test_db
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	// refresh_tokens меняется почти всеми миграциями после первой: ALTER с несколькими действиями, UPDATE, частичные индексы
+	db := Open(t, "users", "refresh_tokens")
+
+	if err := db.Exec(`INSERT INTO users (email, password) VALUES ('alice@example.com', 'hash')`).Error; err != nil {
+		t.Fatal(err)
+	}
+	var user struct {
+		ID        string
+		CreatedAt time.Time
+	}
+	if err := db.Raw(`SELECT id, created_at FROM users`).Scan(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(user.ID) != 36 {
+		t.Errorf("id = %q, want a generated uuid", user.ID)
+	}
+	if time.Since(user.CreatedAt).Abs() > time.Minute {
+		t.Errorf("created_at = %s, want now", user.CreatedAt)
+	}
+
+	var columns []string
+	if err := db.Raw(`SELECT name FROM pragma_table_info('refresh_tokens')`).Scan(&columns).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range []string{"family_id", "amr", "session_started_at", "expires_at"} {
+		if !slices.Contains(columns, column) {
+			t.Errorf("refresh_tokens has no %s column, got %v", column, columns)
+		}
+	}
+}
+
+func TestSplitAlterTable(t *testing.T) {
+	got := splitAlterTable(`ALTER TABLE refresh_tokens
+    ALTER COLUMN session_started_at SET NOT NULL,
+    ADD COLUMN scope TEXT NOT NULL DEFAULT ''`)
+	if len(got) != 1 || got[0] != "ALTER TABLE refresh_tokens ADD COLUMN scope TEXT NOT NULL DEFAULT ''" {
+		t.Errorf("splitAlterTable() = %q", got)
+	}
+}
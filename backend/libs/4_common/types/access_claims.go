@@ -0,0 +1,70 @@
+package types
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Способы входа для claim amr (RFC 8176)
+const (
+	AMRPassword    = "pwd"
+	AMROTP         = "otp"
+	AMRHardwareKey = "hwk"
+	AMRSoftwareKey = "swk"
+	AMRMultiFactor = "mfa"
+	// AMREmailLink — ссылка из письма. В RFC 8176 такого значения нет, otp не подходит:
+	// он означает второй фактор и открывает /admin, пока не выключен ADMIN_REQUIRE_MFA
+	AMREmailLink = "email"
+)
+
+// AccessClaims — содержимое access-токена: зарегистрированные claims из RFC 7519 и наши собственные
+type AccessClaims struct {
+	Issuer    string    `json:"iss,omitempty"`
+	Subject   string    `json:"sub,omitempty"`
+	Audience  []string  `json:"aud,omitempty"`
+	ExpiresAt time.Time `json:"exp"`
+	NotBefore time.Time `json:"nbf"`
+	IssuedAt  time.Time `json:"iat"`
+	ID        string    `json:"jti,omitempty"`
+
+	UserID string `json:"user_id,omitempty"`
+	IP     string `json:"ip,omitempty"`
+	// ClientID — OAuth2-клиент, которому выдан токен. У токенов client_credentials он же sub
+	ClientID string `json:"client_id,omitempty"`
+	// Scope — выданные scope через пробел (RFC 8693, 4.2): permissions ролей и scope OAuth2-клиента
+	Scope string `json:"scope,omitempty"`
+	// Roles — роли пользователя на момент выпуска токена
+	Roles []string `json:"roles,omitempty"`
+	// AMR — чем пользователь подтвердил вход, переносится через /auth/refresh
+	AMR []string `json:"amr,omitempty"`
+	// SessionID — сессия (цепочка refresh-токенов), в которой выпущен токен
+	SessionID string `json:"sid,omitempty"`
+	// APIKeyID заполнен, если запрос аутентифицирован API-ключом, а не токеном
+	APIKeyID string `json:"api_key_id,omitempty"`
+}
+
+func (c *AccessClaims) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(c.Scope), scope)
+}
+
+func (c *AccessClaims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+func (c *AccessClaims) HasAMR(method string) bool {
+	return slices.Contains(c.AMR, method)
+}
+
+// IsClientToken — токен выдан сервису по client_credentials, а не пользователю
+func (c *AccessClaims) IsClientToken() bool {
+	return c.ClientID != "" && c.Subject == c.ClientID
+}
+
+// GetUserID возвращает id пользователя. В токенах старого формата нет sub, только user_id
+func (c *AccessClaims) GetUserID() string {
+	if c.Subject != "" {
+		return c.Subject
+	}
+	return c.UserID
+}
@@ -0,0 +1,66 @@
+package types
+
+type AuditEventType string
+type AuditOutcome string
+
+const (
+	AuditTokenIssued      AuditEventType = "token_issued"
+	AuditTokenRefreshed   AuditEventType = "token_refreshed"
+	AuditRefreshFailed    AuditEventType = "refresh_failed"
+	AuditRefreshReuse     AuditEventType = "refresh_reuse"
+	AuditIPChanged        AuditEventType = "ip_changed"
+	AuditLoginSucceeded   AuditEventType = "login_succeeded"
+	AuditLoginFailed      AuditEventType = "login_failed"
+	AuditAccountLocked    AuditEventType = "account_locked"
+	AuditAccountUnlocked  AuditEventType = "account_unlocked"
+	AuditRateLimitReached AuditEventType = "rate_limit_reached"
+	AuditAccessDenied     AuditEventType = "access_denied"
+	AuditTokenRevoked     AuditEventType = "token_revoked"
+
+	AuditAuthorizationCodeIssued AuditEventType = "authorization_code_issued"
+	AuditAuthorizationCodeReuse  AuditEventType = "authorization_code_reuse"
+
+	AuditRoleAssigned AuditEventType = "role_assigned"
+	AuditRoleRemoved  AuditEventType = "role_removed"
+
+	AuditAPIKeyCreated AuditEventType = "api_key_created"
+	AuditAPIKeyRotated AuditEventType = "api_key_rotated"
+	AuditAPIKeyRevoked AuditEventType = "api_key_revoked"
+
+	AuditMFAEnabled               AuditEventType = "mfa_enabled"
+	AuditMFADisabled              AuditEventType = "mfa_disabled"
+	AuditMFASucceeded             AuditEventType = "mfa_succeeded"
+	AuditMFAFailed                AuditEventType = "mfa_failed"
+	AuditRecoveryCodesRegenerated AuditEventType = "recovery_codes_regenerated"
+
+	AuditPasskeyRegistered AuditEventType = "passkey_registered"
+	AuditPasskeyDeleted    AuditEventType = "passkey_deleted"
+
+	AuditPasswordResetRequested AuditEventType = "password_reset_requested"
+	AuditPasswordReset          AuditEventType = "password_reset"
+
+	AuditEmailVerificationSent AuditEventType = "email_verification_sent"
+	AuditEmailVerified         AuditEventType = "email_verified"
+	AuditEmailChangeRequested  AuditEventType = "email_change_requested"
+	AuditEmailChanged          AuditEventType = "email_changed"
+
+	AuditMagicLinkSent AuditEventType = "magic_link_sent"
+
+	AuditSessionRevoked      AuditEventType = "session_revoked"
+	AuditSessionLimitReached AuditEventType = "session_limit_reached"
+)
+
+const (
+	AuditSuccess AuditOutcome = "success"
+	AuditFailure AuditOutcome = "failure"
+)
+
+type AuditEvent struct {
+	UserID    string
+	IPAddress string
+	UserAgent string
+	EventType AuditEventType
+	Outcome   AuditOutcome
+	RequestID string
+	Details   Fields
+}
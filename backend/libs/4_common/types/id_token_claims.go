@@ -0,0 +1,33 @@
+package types
+
+import "time"
+
+// IDTokenClaims — содержимое OpenID Connect ID-токена (OIDC Core, 2). Audience — client_id
+type IDTokenClaims struct {
+	Issuer          string
+	Subject         string
+	Audience        []string
+	AuthorizedParty string
+	ExpiresAt       time.Time
+	IssuedAt        time.Time
+	AuthTime        time.Time
+	Nonce           string
+	AMR             []string
+
+	Email         string
+	EmailVerified bool
+}
+
+// JSONWebKey — открытый ключ для проверки подписи ID-токенов (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
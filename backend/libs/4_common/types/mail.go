@@ -0,0 +1,8 @@
+package types
+
+// MailMessage — письмо пользователю, только текст
+type MailMessage struct {
+	To      string
+	Subject string
+	Text    string
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit описывает token bucket: Burst токенов, пополняется со скоростью Rate токенов в секунду
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// ParseRateLimit разбирает лимит в формате "<count>/<period>", например "10/m", "100/h" или "5/30s".
+// Пустая строка, "0" и "off" означают отсутствие лимита.
+func ParseRateLimit(value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || strings.EqualFold(value, "off") {
+		return RateLimit{}, nil
+	}
+
+	countStr, periodStr, ok := strings.Cut(value, "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: expected <count>/<period>", value)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil || count < 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit count %q", countStr)
+	}
+
+	var period time.Duration
+	switch periodStr = strings.TrimSpace(periodStr); periodStr {
+	case "s":
+		period = time.Second
+	case "m":
+		period = time.Minute
+	case "h":
+		period = time.Hour
+	case "d":
+		period = 24 * time.Hour
+	default:
+		period, err = time.ParseDuration(periodStr)
+		if err != nil || period <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit period %q", periodStr)
+		}
+	}
+
+	return RateLimit{
+		Rate:  float64(count) / period.Seconds(),
+		Burst: count,
+	}, nil
+}
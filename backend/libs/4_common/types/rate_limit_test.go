@@ -0,0 +1,58 @@
+package types
+
+import "testing"
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{"", RateLimit{}, false},
+		{"0", RateLimit{}, false},
+		{"off", RateLimit{}, false},
+		{" OFF ", RateLimit{}, false},
+		{"10/s", RateLimit{Rate: 10, Burst: 10}, false},
+		{"30/m", RateLimit{Rate: 0.5, Burst: 30}, false},
+		{"3600/h", RateLimit{Rate: 1, Burst: 3600}, false},
+		{"86400/d", RateLimit{Rate: 1, Burst: 86400}, false},
+		{"5/30s", RateLimit{Rate: 5 / 30.0, Burst: 5}, false},
+		{" 20 / m ", RateLimit{Rate: 20 / 60.0, Burst: 20}, false},
+		// 0 запросов за период — лимит выключен, а не «запрещено всё»
+		{"0/m", RateLimit{Rate: 0, Burst: 0}, false},
+		{"10", RateLimit{}, true},
+		{"ten/m", RateLimit{}, true},
+		{"-1/m", RateLimit{}, true},
+		{"10/week", RateLimit{}, true},
+		{"10/0s", RateLimit{}, true},
+		{"10/-1m", RateLimit{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRateLimit(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRateLimit(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRateLimit(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"off": false, "0/m": false, "1/h": true} {
+		limit, err := ParseRateLimit(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if limit.Enabled() != want {
+			t.Errorf("ParseRateLimit(%q).Enabled() = %v, want %v", value, limit.Enabled(), want)
+		}
+	}
+
+	if (RateLimit{Rate: 1}).Enabled() || (RateLimit{Burst: 1}).Enabled() {
+		t.Error("limit without rate or burst must be disabled")
+	}
+}
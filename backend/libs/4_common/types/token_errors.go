@@ -0,0 +1,14 @@
+package types
+
+import "errors"
+
+// Ошибки проверки токенов не зависят от JWT-библиотеки, чтобы хэндлеры могли отвечать по ним точно
+var (
+	ErrTokenMalformed     = errors.New("token is malformed")
+	ErrTokenSignature     = errors.New("token signature is invalid")
+	ErrTokenExpired       = errors.New("token is expired")
+	ErrTokenNotYetValid   = errors.New("token is not valid yet")
+	ErrTokenIssuer        = errors.New("token has wrong issuer")
+	ErrTokenAudience      = errors.New("token has wrong audience")
+	ErrTokenMissingClaims = errors.New("token is missing required claims")
+)
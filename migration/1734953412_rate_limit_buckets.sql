@@ -0,0 +1,7 @@
+CREATE TABLE rate_limit_buckets (
+    key TEXT PRIMARY KEY NOT NULL,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX rate_limit_buckets_updated_at_idx ON rate_limit_buckets (updated_at);
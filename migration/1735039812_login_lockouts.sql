@@ -0,0 +1,12 @@
+CREATE TABLE login_lockouts (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    subject_type TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    lockout_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    UNIQUE (subject_type, subject)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE audit_events (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    user_id TEXT,
+    ip_address TEXT,
+    user_agent TEXT,
+    event_type TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    request_id TEXT,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_events_created_at_idx ON audit_events (created_at DESC, id DESC);
+CREATE INDEX audit_events_user_id_idx ON audit_events (user_id, created_at DESC);
+CREATE INDEX audit_events_event_type_idx ON audit_events (event_type, created_at DESC);
@@ -0,0 +1,19 @@
+CREATE TABLE revoked_tokens (
+    jti TEXT PRIMARY KEY NOT NULL,
+    user_id TEXT NOT NULL,
+    reason TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
+
+CREATE TABLE user_token_revocations (
+    user_id TEXT PRIMARY KEY NOT NULL,
+    revoked_before TIMESTAMP NOT NULL,
+    reason TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX user_token_revocations_expires_at_idx ON user_token_revocations (expires_at);
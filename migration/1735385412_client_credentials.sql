@@ -0,0 +1,7 @@
+-- пустой secret_hash — публичный клиент (SPA, мобильное приложение), client_credentials ему недоступен
+ALTER TABLE clients
+    ADD COLUMN secret_hash TEXT NOT NULL DEFAULT '',
+    ADD COLUMN previous_secret_hash TEXT NOT NULL DEFAULT '',
+    ADD COLUMN previous_secret_expires_at TIMESTAMP,
+    ADD COLUMN allowed_scopes TEXT NOT NULL DEFAULT '',
+    ADD COLUMN access_token_ttl_seconds INTEGER NOT NULL DEFAULT 0;
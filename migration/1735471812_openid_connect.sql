@@ -0,0 +1,9 @@
+ALTER TABLE authorization_codes
+    ADD COLUMN nonce TEXT NOT NULL DEFAULT '',
+    ADD COLUMN auth_time TIMESTAMP NOT NULL DEFAULT NOW(),
+    ADD COLUMN amr TEXT NOT NULL DEFAULT '';
+
+-- refresh-токен помнит клиента и scope, чтобы /auth/refresh выдавал access-токен с теми же правами
+ALTER TABLE refresh_tokens
+    ADD COLUMN client_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN scope TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    -- открытая часть ключа для поиска и отображения, сам ключ хранится только как хэш
+    prefix TEXT UNIQUE NOT NULL,
+    hashed_key TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    owner_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    scopes TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX api_keys_owner_id_idx ON api_keys (owner_id);
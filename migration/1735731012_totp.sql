@@ -0,0 +1,37 @@
+-- TOTP (RFC 6238): не больше одного секрета на пользователя, подтверждённый включает второй фактор
+CREATE TABLE user_totp (
+    user_id TEXT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    -- секрет зашифрован AES-256-GCM ключом MFA_ENCRYPTION_KEY
+    encrypted_secret TEXT NOT NULL,
+    confirmed_at TIMESTAMP,
+    -- последний принятый 30-секундный шаг: один и тот же код дважды не принимается
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE mfa_recovery_codes (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX mfa_recovery_codes_user_id_code_hash_idx ON mfa_recovery_codes (user_id, code_hash);
+
+-- второй шаг входа: выдаётся после пароля и обменивается на пару токенов вместе с кодом
+CREATE TABLE mfa_challenges (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    used BOOLEAN NOT NULL DEFAULT false,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX mfa_challenges_expires_at_idx ON mfa_challenges (expires_at);
+
+-- способы входа (amr, RFC 8176) переносятся в access-токены после /auth/refresh
+ALTER TABLE refresh_tokens ADD COLUMN amr TEXT NOT NULL DEFAULT '';
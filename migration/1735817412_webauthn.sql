@@ -0,0 +1,33 @@
+-- passkeys (WebAuthn): открытые ключи аутентификаторов пользователей
+CREATE TABLE webauthn_credentials (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    credential_id BYTEA UNIQUE NOT NULL,
+    -- ключ в формате COSE, как его вернул аутентификатор
+    public_key BYTEA NOT NULL,
+    sign_count BIGINT NOT NULL DEFAULT 0,
+    -- через пробел: usb, nfc, ble, internal, hybrid
+    transports TEXT NOT NULL DEFAULT '',
+    attestation_type TEXT NOT NULL DEFAULT '',
+    aaguid BYTEA,
+    -- флаги authenticatorData (UP, UV, BE, BS) последней успешной проверки
+    flags SMALLINT NOT NULL DEFAULT 0,
+    name TEXT NOT NULL DEFAULT '',
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webauthn_credentials_user_id_idx ON webauthn_credentials (user_id);
+
+-- challenge одной церемонии регистрации или входа, удаляется при использовании
+CREATE TABLE webauthn_challenges (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    ceremony TEXT NOT NULL,
+    -- пусто для входа по passkey: пользователь станет известен из ответа аутентификатора
+    user_id TEXT NOT NULL DEFAULT '',
+    session_data TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX webauthn_challenges_expires_at_idx ON webauthn_challenges (expires_at);
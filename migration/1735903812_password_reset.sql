@@ -0,0 +1,13 @@
+-- восстановление пароля: одноразовый токен из письма, хранится только SHA-256
+CREATE TABLE password_reset_tokens (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL DEFAULT '',
+    used_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX password_reset_tokens_user_id_idx ON password_reset_tokens (user_id);
+CREATE INDEX password_reset_tokens_expires_at_idx ON password_reset_tokens (expires_at);
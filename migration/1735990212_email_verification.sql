@@ -0,0 +1,16 @@
+-- подтверждение email: пусто — адрес не подтверждён. Существующие пользователи тоже считаются неподтверждёнными
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+-- токен из письма подтверждает адрес email: текущий адрес пользователя или новый при смене email
+CREATE TABLE email_verification_tokens (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    used_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX email_verification_tokens_user_id_idx ON email_verification_tokens (user_id);
+CREATE INDEX email_verification_tokens_expires_at_idx ON email_verification_tokens (expires_at);
@@ -0,0 +1,18 @@
+-- вход по ссылке из письма: хранится SHA-256 случайной части ссылки, подпись проверяется ключом сервиса
+CREATE TABLE magic_links (
+    id TEXT PRIMARY KEY DEFAULT gen_random_uuid() NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    -- SHA-256 токена устройства, запросившего ссылку; пусто — ссылка не привязана к устройству
+    device_hash TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    used_at TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX magic_links_user_id_idx ON magic_links (user_id);
+CREATE INDEX magic_links_expires_at_idx ON magic_links (expires_at);
+
+-- первый фактор входа (amr) для второго шага: пароль или ссылка из письма
+ALTER TABLE mfa_challenges ADD COLUMN amr TEXT NOT NULL DEFAULT 'pwd';
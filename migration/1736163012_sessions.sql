@@ -0,0 +1,16 @@
+-- сессия — цепочка refresh-токенов от одного входа: при обновлении новый токен наследует family_id.
+-- У выданных раньше токенов сессией считается сама строка
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id TEXT,
+    ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+    ADD COLUMN device_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- отозванный токен не путается с использованным: его предъявление — не признак кражи
+    ADD COLUMN revoked_at TIMESTAMP;
+
+UPDATE refresh_tokens SET family_id = id, last_used_at = COALESCE(created_at, NOW());
+
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
+CREATE INDEX refresh_tokens_active_user_id_idx ON refresh_tokens (user_id) WHERE used = false AND revoked_at IS NULL;
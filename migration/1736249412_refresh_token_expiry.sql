@@ -0,0 +1,14 @@
+-- сроки refresh-токенов: session_started_at — вход, с которого началась сессия, переносится при обновлении.
+-- expires_at — самый ранний из сроков на момент выпуска; у выданных раньше токенов его нет,
+-- для них сроки считаются по created_at и session_started_at
+ALTER TABLE refresh_tokens
+    ADD COLUMN session_started_at TIMESTAMP,
+    ADD COLUMN expires_at TIMESTAMP;
+
+UPDATE refresh_tokens SET session_started_at = COALESCE(created_at, NOW());
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN session_started_at SET NOT NULL,
+    ALTER COLUMN session_started_at SET DEFAULT NOW();
+
+CREATE INDEX refresh_tokens_expires_at_idx ON refresh_tokens (expires_at);
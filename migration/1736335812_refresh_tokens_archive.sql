@@ -0,0 +1,25 @@
+-- архив refresh-токенов, которые вычистил janitor (TOKEN_JANITOR_MODE=archive). Хэш секрета не переносится
+CREATE TABLE refresh_tokens_archive (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    family_id TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    device_name TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    scope TEXT NOT NULL,
+    amr TEXT NOT NULL,
+    used BOOLEAN,
+    created_at TIMESTAMP,
+    last_used_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    session_started_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX refresh_tokens_archive_user_id_idx ON refresh_tokens_archive (user_id);
+
+-- janitor выбирает использованные и отозванные токены по возрасту, истёкшие — по refresh_tokens_expires_at_idx
+CREATE INDEX refresh_tokens_used_created_at_idx ON refresh_tokens (created_at) WHERE used = true;
+CREATE INDEX refresh_tokens_revoked_at_idx ON refresh_tokens (revoked_at) WHERE revoked_at IS NOT NULL;
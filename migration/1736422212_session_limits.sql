@@ -0,0 +1,4 @@
+-- лимит активных сессий: 0 — не задан (берётся лимит ролей, затем SESSION_LIMIT), отрицательный — без ограничения.
+-- Собственный лимит пользователя важнее лимитов его ролей
+ALTER TABLE users ADD COLUMN max_sessions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE roles ADD COLUMN max_sessions INTEGER NOT NULL DEFAULT 0;